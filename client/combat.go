@@ -0,0 +1,43 @@
+package client
+
+import (
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// When the player last attacked, to match the server's cooldown
+var lastAttackTime float64
+
+// Finds the entity the player is looking at within reach, unless a block is in the way
+func targetedEntity(dim *core.Dimension) core.Entity {
+	e, t := core.TraceEntities(player.LookDir(), player.CameraPos(), core.ATTACK_REACH, &dim.Entities, player.ID)
+	if e == nil {
+		return nil
+	}
+
+	var blocked bool
+	core.TraceBlocks(dim, player.LookDir(), player.CameraPos(), t, func(core.Block, mgl32.Vec3) (stop bool) {
+		blocked = true
+		return true
+	})
+	if blocked {
+		return nil
+	}
+
+	return e
+}
+
+// Attacks the entity, if the cooldown has passed since the last attack
+func attackEntity(e core.Entity) {
+	now := glfw.GetTime()
+	if now-lastAttackTime < core.ATTACK_COOLDOWN {
+		return
+	}
+	lastAttackTime = now
+
+	serverWrite <- proto.ENTITY_ATTACK
+	serverWrite <- proto.EntityAttack(e.GetID())
+}
@@ -3,11 +3,54 @@ package client
 import (
 	"remakemc/client/renderers"
 	"remakemc/core"
+	"remakemc/core/container"
 	"remakemc/core/proto"
 
 	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/google/uuid"
 )
 
+// Creates the client's view of a container opened by the server, from the
+// type of the entity holding it
+func NewContainerView(containerType string, entityID uuid.UUID) core.Container {
+	var c core.SharedContainer
+	switch containerType {
+	case "mc:chest":
+		c = new(container.Chest)
+	default:
+		return nil
+	}
+
+	c.Init(true, entityID)
+	return c.ViewWith(player.Inventory)
+}
+
+// Creates the creative palette, viewed with the player's inventory.
+// The palette isn't opened by the server, as it's the same for everyone.
+func newCreativeView() core.Container {
+	c := new(container.Creative)
+	c.Init(true, container.CreativeID)
+	return c.ViewWith(player.Inventory)
+}
+
+// The maximum time between clicks for them to count as a double click, in seconds
+const doubleClickTime = 0.25
+
+var numberKeys [9]core.Debounced
+
+// The revision of the player's containers, including predicted changes.
+// See proto.ContainerClick
+var containerRevision int
+
+// The state of a drag across several slots with the floating stack
+var dragging bool
+var dragButton glfw.MouseButton
+var dragSlots []int
+
+// The last slot left clicked, used to detect double clicks
+var lastClickSlot = -1
+var lastClickTime float64
+
 func ProcessContainerInteraction(c core.Container) {
 	// Convert the location of the cursor into OpenGL coordinates
 	xpos, ypos := renderers.Win.GetCursorPos()
@@ -16,40 +59,133 @@ func ProcessContainerInteraction(c core.Container) {
 	cursorY := float32(-ypos/float64(height)*2 + 1)
 
 	// Determine hovered slot
-	var hovered core.Slot
-	var slotIndex int
+	slotIndex := -1
 	for k, v := range c.GetSlots() {
 		start, end := v.GetBox()
 		if start.X() < cursorX && end.X() > cursorX && start.Y() < cursorY && end.Y() > cursorY {
-			hovered = v
 			slotIndex = k
 		}
 	}
 
-	if hovered == nil {
+	// Continue any drag in progress
+	if dragging {
+		if renderers.Win.GetMouseButton(dragButton) == glfw.Release {
+			finishDrag(c)
+		} else if slotIndex >= 0 {
+			var found bool
+			for _, v := range dragSlots {
+				if v == slotIndex {
+					found = true
+					break
+				}
+			}
+			if !found {
+				dragSlots = append(dragSlots, slotIndex)
+			}
+		}
+		return
+	}
+
+	if slotIndex < 0 {
 		// TODO drop items (if outside inventory)
 		return
 	}
 
+	// Swap with hotbar slot. Number keys are typed into text input containers instead.
+	_, textInput := c.(textInputContainer)
+	for i := glfw.Key1; i <= glfw.Key9 && !textInput; i++ {
+		n := int(i - glfw.Key1)
+		if renderers.Win.GetKey(i) == glfw.Press && numberKeys[n].Invoke() {
+			sendContainerClick(c, proto.ContainerClick{
+				SlotIndex: slotIndex,
+				NumberKey: n + 1,
+			})
+		} else if renderers.Win.GetKey(i) == glfw.Release {
+			numberKeys[n].Reset()
+		}
+	}
+
+	shift := renderers.Win.GetKey(glfw.KeyLeftShift)
+
 	// Left click
 	if renderers.Win.GetMouseButton(glfw.MouseButton1) == glfw.Press && mouseOne.Invoke() {
-		serverWrite <- proto.CONTAINER_CLICK
-		serverWrite <- proto.ContainerClick{
-			EntityID:  c.GetEntityID(),
-			SlotIndex: slotIndex,
-			LeftClick: true,
+		now := glfw.GetTime()
+		doubleClick := lastClickSlot == slotIndex && now-lastClickTime < doubleClickTime
+		lastClickSlot = slotIndex
+		lastClickTime = now
+
+		if shift != glfw.Press && !doubleClick && !c.GetFloating().IsEmpty() {
+			// Placing may become a drag
+			startDrag(glfw.MouseButton1, slotIndex)
+		} else {
+			sendContainerClick(c, proto.ContainerClick{
+				SlotIndex:   slotIndex,
+				LeftClick:   true,
+				ShiftKey:    shift,
+				DoubleClick: doubleClick && shift != glfw.Press,
+			})
 		}
 	}
 
 	// Right click
 	if renderers.Win.GetMouseButton(glfw.MouseButton2) == glfw.Press && mouseTwo.Invoke() {
-		serverWrite <- proto.CONTAINER_CLICK
-		serverWrite <- proto.ContainerClick{
-			EntityID:   c.GetEntityID(),
-			SlotIndex:  slotIndex,
-			RightClick: true,
+		if shift != glfw.Press && !c.GetFloating().IsEmpty() {
+			startDrag(glfw.MouseButton2, slotIndex)
+		} else {
+			sendContainerClick(c, proto.ContainerClick{
+				SlotIndex:  slotIndex,
+				RightClick: true,
+				ShiftKey:   shift,
+			})
+		}
+	}
+}
+
+func startDrag(button glfw.MouseButton, slotIndex int) {
+	dragging = true
+	dragButton = button
+	dragSlots = []int{slotIndex}
+}
+
+// Send the drag to the server once the mouse button has been released.
+// A drag over a single slot is a normal click.
+func finishDrag(c core.Container) {
+	dragging = false
+
+	if len(dragSlots) == 1 {
+		sendContainerClick(c, proto.ContainerClick{
+			SlotIndex:  dragSlots[0],
+			LeftClick:  dragButton == glfw.MouseButton1,
+			RightClick: dragButton == glfw.MouseButton2,
+		})
+	} else {
+		m := proto.ContainerDrag{
+			EntityID:    c.GetEntityID(),
+			SlotIndices: dragSlots,
+			Revision:    containerRevision,
+			RightClick:  dragButton == glfw.MouseButton2,
 		}
+
+		// Predict the result, until the server tells us otherwise
+		m.Apply(c)
+		containerRevision++
+
+		serverWrite <- proto.CONTAINER_DRAG
+		serverWrite <- m
 	}
 
-	// TODO painting mode
+	dragSlots = nil
+}
+
+// Applies the click to the container and sends it to the server
+func sendContainerClick(c core.Container, m proto.ContainerClick) {
+	m.EntityID = c.GetEntityID()
+	m.Revision = containerRevision
+
+	// Predict the result, until the server tells us otherwise
+	m.Apply(c)
+	containerRevision++
+
+	serverWrite <- proto.CONTAINER_CLICK
+	serverWrite <- m
 }
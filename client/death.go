@@ -0,0 +1,50 @@
+package client
+
+import (
+	"remakemc/client/gui"
+	"remakemc/client/renderers"
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Whether the player has clicked respawn, and is waiting for the server
+var respawnRequested bool
+
+// Shows the respawn screen, and asks the server to respawn the player when clicked
+func ProcessRespawnScreen() {
+	gui.RenderRespawnScreen()
+
+	if !respawnRequested && renderers.Win.GetMouseButton(glfw.MouseButton1) == glfw.Press && mouseOne.Invoke() {
+		respawnRequested = true
+		serverWrite <- proto.PLAYER_RESPAWN
+	}
+}
+
+// Updates the player's health, showing the respawn screen if they died
+func setHealth(h proto.PlayerHealth) {
+	player.Health = float32(h)
+	if !player.Dead() {
+		return
+	}
+
+	// The server has already closed any container
+	if containerOpen {
+		hideContainer()
+	}
+	respawnRequested = false
+	renderers.Win.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+}
+
+func respawn(r proto.PlayerRespawn) {
+	player.Position = r.Position
+	player.Velocity = mgl32.Vec3{}
+	player.TakeLandingSpeed()
+	player.resetPrediction()
+	player.Health = r.Health
+	player.HungerComp = core.NewHungerComp()
+
+	renderers.Win.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+}
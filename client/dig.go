@@ -0,0 +1,100 @@
+package client
+
+import (
+	"fmt"
+	"remakemc/client/gui"
+	"remakemc/client/renderers"
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The block being dug in survival, and for how long
+var digging bool
+var digPosition core.Vec3
+var digTime float64
+
+// Digs the block the player is looking at while the left mouse button is held,
+// or attacks the entity they are looking at.
+// Survival players must hold it for the block's hardness, and creative players
+// break blocks instantly.
+func DigSystem(dim *core.Dimension, deltaT float64) {
+	if renderers.Win.GetMouseButton(glfw.MouseButton1) == glfw.Release {
+		digging = false
+		mouseOne.Reset()
+		return
+	}
+	if containerOpen || player.Dead() || !player.GameMode.CanInteract() {
+		digging = false
+		return
+	}
+
+	// Attack entities in front of any block
+	if e := targetedEntity(dim); e != nil {
+		digging = false
+		if mouseOne.Invoke() {
+			attackEntity(e)
+		}
+		return
+	}
+
+	// Find the targeted block
+	var block core.Block
+	var hit mgl32.Vec3
+	core.TraceBlocks(dim, player.LookDir(), player.CameraPos(), core.BLOCK_REACH, func(b core.Block, h mgl32.Vec3) (stop bool) {
+		block, hit = b, h
+		return true
+	})
+	if block.Type == nil {
+		digging = false
+		return
+	}
+
+	if player.GameMode.InstantBreak() {
+		// One block per click
+		if mouseOne.Invoke() {
+			serverWrite <- proto.BLOCK_DIG
+			serverWrite <- proto.BlockDig{
+				Position:      block.Position,
+				SubvoxelHit:   hit,
+				FinishDigging: true,
+			}
+		}
+		return
+	}
+
+	// Start digging again whenever the target changes
+	if !digging || digPosition != block.Position {
+		digging = true
+		digPosition = block.Position
+		digTime = 0
+
+		serverWrite <- proto.BLOCK_DIG
+		serverWrite <- proto.BlockDig{
+			Position:    block.Position,
+			SubvoxelHit: hit,
+		}
+	}
+
+	digTime += deltaT
+	if digTime >= float64(block.Type.Hardness) {
+		digging = false
+
+		serverWrite <- proto.BLOCK_DIG
+		serverWrite <- proto.BlockDig{
+			Position:      block.Position,
+			SubvoxelHit:   hit,
+			FinishDigging: true,
+		}
+		return
+	}
+
+	// TODO Render cracks on the block instead
+	gui.RenderText(
+		mgl32.Vec2{0, -0.1},
+		fmt.Sprintf("%d%%", int(digTime/float64(block.Type.Hardness)*100)),
+		gui.Anchor{Horizontal: 0, Vertical: 1},
+	)
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"log"
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// Estimates the server's time, to show remote entities a little in the past
+var entityClock = core.NewInterpolationClock()
+
+// Creates the client's copy of an entity created by the server, from its type.
+// Remote entities are only moved by the server, so don't run physics or AI.
+func newRemoteEntity(msg proto.EntityCreate) core.Entity {
+	t := core.EntityRegistry[msg.EntityType]
+	if t == nil {
+		return nil
+	}
+
+	e := t.New(msg.EntityID)
+	if t.DecodeSpawn != nil && msg.SpawnData != nil {
+		if err := t.DecodeSpawn(e, msg.SpawnData); err != nil {
+			log.Println("Error decoding spawn data of", msg.EntityType, err)
+			return nil
+		}
+	}
+
+	if p, ok := e.(core.PositionFace); ok {
+		*p.GetPosition() = msg.Position
+	}
+	if p, ok := e.(core.PhysicsFace); ok {
+		p.GetPhysicsComp().AABB = msg.AABB
+		p.GetPhysicsComp().Velocity = mgl32.Vec3{}
+		p.GetPhysicsComp().NoGravity = true
+		p.GetPhysicsComp().NoClip = true
+	}
+	if l, ok := e.(core.LookFace); ok {
+		l.GetLookComp().Yaw = msg.Yaw
+		l.GetLookComp().Azimuth = msg.LookAzimuth
+		l.GetLookComp().Elevation = msg.LookElevation
+	}
+	if m, ok := e.(core.AIFace); ok {
+		m.GetAIComp().Goals = nil
+	}
+
+	// Start still at the position
+	entityClock.Observe(msg.Time)
+	if l, ok := e.(core.LerpFace); ok {
+		l.GetLerpComp().AddSnapshot(snapshotFromPosition(msg.EntityPosition))
+	}
+	return e
+}
+
+// Updates the position of an entity, or corrects the player's position
+func updateEntityPosition(dim *core.Dimension, msg proto.EntityPosition) {
+	if msg.EntityID == player.ID {
+		// Only happens when the server rejects the player's movement
+		player.reconcile(dim, msg)
+		return
+	}
+
+	entityClock.Observe(msg.Time)
+
+	v := dim.Entities.Get(msg.EntityID)
+	if v == nil {
+		return
+	}
+
+	// The LerpSystem moves the entity once the client is showing the time
+	// of the position
+	if l, ok := v.(core.LerpFace); ok {
+		l.GetLerpComp().AddSnapshot(snapshotFromPosition(msg))
+		return
+	}
+
+	if l, ok := v.(core.LookFace); ok {
+		l.GetLookComp().Yaw = msg.Yaw
+		l.GetLookComp().Azimuth = msg.LookAzimuth
+		l.GetLookComp().Elevation = msg.LookElevation
+	}
+	if p, ok := v.(core.PositionFace); ok {
+		*p.GetPosition() = msg.Position
+		dim.Entities.Moved(v)
+	}
+}
+
+func snapshotFromPosition(msg proto.EntityPosition) core.Snapshot {
+	return core.Snapshot{
+		Time:      msg.Time,
+		Position:  msg.Position,
+		Yaw:       msg.Yaw,
+		Azimuth:   msg.LookAzimuth,
+		Elevation: msg.LookElevation,
+	}
+}
+
+// Updates what the player can see of an entity, and plays its animations
+func updateEntityMetadata(dim *core.Dimension, msg proto.EntityMetadata) {
+	v, ok := dim.Entities.Get(msg.EntityID).(core.MetadataFace)
+	if !ok {
+		return
+	}
+
+	m := v.GetMetadataComp()
+	m.Metadata = msg.Metadata
+	for _, a := range msg.Animations {
+		m.PlayAnimation(a)
+	}
+
+	if s, ok := v.(interface{ SetSneaking(bool) }); ok {
+		s.SetSneaking(msg.Metadata.Sneaking)
+	}
+}
+
+func deleteEntity(dim *core.Dimension, entityID uuid.UUID) {
+	dim.Entities.Remove(entityID)
+}
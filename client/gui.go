@@ -3,6 +3,7 @@ package client
 import (
 	"remakemc/client/renderers"
 	"remakemc/core"
+	"remakemc/core/proto"
 
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
@@ -10,16 +11,55 @@ import (
 var containerOpen bool
 var openContainer core.Container
 
+// A container which can be typed into, such as to search it
+type textInputContainer interface {
+	InputChar(r rune)
+	InputBackspace()
+}
+
+// A container with more rows than are shown, which the mouse wheel scrolls
+type scrollableContainer interface {
+	ScrollRows(rows int)
+}
+
 func OpenContainer(c core.Container) {
 	containerOpen = true
 	renderers.Win.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
 	// renderers.Win.SetScrollCallback(nil)
 	openContainer = c
+
+	if t, ok := c.(textInputContainer); ok {
+		renderers.Win.SetCharCallback(func(_ *glfw.Window, char rune) {
+			t.InputChar(char)
+		})
+	}
+	if s, ok := c.(scrollableContainer); ok {
+		renderers.Win.SetScrollCallback(func(_ *glfw.Window, _, yoff float64) {
+			if yoff < 0 {
+				s.ScrollRows(1)
+			} else if yoff > 0 {
+				s.ScrollRows(-1)
+			}
+		})
+	}
 }
 
+// Closes the open container, and informs the server
 func CloseContainer() {
+	serverWrite <- proto.CONTAINER_CLOSE
+	serverWrite <- proto.ContainerClose(openContainer.GetEntityID())
+
+	hideContainer()
+}
+
+// Closes the open container without informing the server.
+// Used when the server closes the container itself.
+func hideContainer() {
 	containerOpen = false
+	dragging = false
 	renderers.Win.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
 	// renderers.Win.SetScrollCallback(player.ScrollCallback)
+	renderers.Win.SetScrollCallback(nil)
+	renderers.Win.SetCharCallback(nil)
 	openContainer = nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"remakemc/client/renderers"
 	"remakemc/core"
+	"sort"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -77,3 +78,85 @@ func RenderFloating(stack core.ItemStack, slotSize float32) {
 		RenderText(p, fmt.Sprint(stack.Count), Anchor{Horizontal: 1, Vertical: -1})
 	}
 }
+
+// Renders the name, lore, and other properties of the stack in the hovered slot
+func RenderTooltip(slots []core.Slot) {
+	// Convert the location of the cursor into OpenGL coordinates
+	xpos, ypos := renderers.Win.GetCursorPos()
+	width, height := renderers.Win.GetSize()
+	cursorX := float32(xpos/float64(width)*2 - 1)
+	cursorY := float32(-ypos/float64(height)*2 + 1)
+
+	var stack core.ItemStack
+	for _, v := range slots {
+		start, end := v.GetBox()
+		if start.X() < cursorX && end.X() > cursorX && start.Y() < cursorY && end.Y() > cursorY {
+			stack = v.GetStack()
+		}
+	}
+	if stack.IsEmpty() {
+		return
+	}
+
+	lines := tooltipLines(stack)
+
+	// Size the box to fit the longest line
+	var longest int
+	for _, v := range lines {
+		if len(v) > longest {
+			longest = len(v)
+		}
+	}
+	padding := float32(FONT_SIZE / 2)
+	lineHeight := FONT_SIZE / fontRatio * renderers.GetAspectRatio()
+	boxWidth := float32(longest)*FONT_SIZE + padding*2
+	boxHeight := float32(len(lines))*lineHeight + padding*2*renderers.GetAspectRatio()
+
+	// Place the box to the top right of the cursor
+	start := mgl32.Vec2{cursorX + padding, cursorY - boxHeight}
+	end := mgl32.Vec2{cursorX + padding + boxWidth, cursorY}
+	renderers.RenderGUIElement(tooltip, start, end)
+
+	for k, v := range lines {
+		if v == "" {
+			continue
+		}
+
+		RenderText(
+			mgl32.Vec2{start.X() + padding, end.Y() - padding*renderers.GetAspectRatio() - float32(k)*lineHeight},
+			v,
+			Anchor{Horizontal: -1, Vertical: 1},
+		)
+	}
+}
+
+func tooltipLines(stack core.ItemStack) []string {
+	lines := []string{stack.GetDisplayName()}
+
+	if lore, ok := core.MetaLore.Get(stack); ok {
+		lines = append(lines, lore...)
+	}
+
+	if mods, ok := core.MetaModifiers.Get(stack); ok {
+		var names []string
+		for k := range mods {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		for _, v := range names {
+			lines = append(lines, fmt.Sprint(v, " ", mods[v]))
+		}
+	}
+
+	if t := core.ItemRegistry[stack.Item]; t != nil && t.AttackDamage > 0 {
+		lines = append(lines, fmt.Sprintf("%v Attack Damage", t.AttackDamage))
+	}
+
+	if t := core.ItemRegistry[stack.Item]; t != nil && t.MaxDamage > 0 {
+		damage, _ := core.MetaDamage.Get(stack)
+		lines = append(lines, fmt.Sprintf("Durability: %v / %v", t.MaxDamage-damage, t.MaxDamage))
+	}
+
+	return lines
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-func RenderGame(selectedHotbarSlot int, hotbarItems []core.ItemStack) {
+// Renders the crosshair, hotbar, and the player's health and hunger above it.
+// The health and hunger are hidden if they are nil.
+func RenderGame(selectedHotbarSlot int, hotbarItems []core.ItemStack, health *core.HealthComp, hunger *core.HungerComp) {
 	RenderWithAnchor(crosshair, mgl32.Vec2{0, 0}, mgl32.Vec2{0.03, 0.03}, Anchor{0, 0})
 
 	// Render hotbar
@@ -57,5 +59,53 @@ func RenderGame(selectedHotbarSlot int, hotbarItems []core.ItemStack) {
 				)
 			}
 		}
+
+		iconWidth := hwidth / 182 * 9
+		iconAdvance := hwidth / 182 * 8
+		y := -1 + (selectorWidth-slotWidth)/2*renderers.GetAspectRatio() + (slotWidth+hwidth/182)*renderers.GetAspectRatio()
+
+		// Render a row of hearts, with each heart being two health
+		if health != nil {
+
+			for i := 0; i < int(health.MaxHealth+1)/2; i++ {
+				heart := heartEmpty
+				if health.Health >= float32(i*2+2) {
+					heart = heartFull
+				} else if health.Health >= float32(i*2+1) {
+					heart = heartHalf
+				}
+
+				RenderWithAnchor(heart,
+					mgl32.Vec2{-hwidth/2 + iconAdvance*float32(i), y},
+					mgl32.Vec2{iconWidth, iconWidth},
+					Anchor{Horizontal: -1, Vertical: -1},
+				)
+			}
+		}
+
+		// Render a row of food from the right, with each being two food
+		if hunger != nil {
+			for i := 0; i < core.MAX_FOOD/2; i++ {
+				food := foodEmpty
+				if hunger.Food >= i*2+2 {
+					food = foodFull
+				} else if hunger.Food >= i*2+1 {
+					food = foodHalf
+				}
+
+				RenderWithAnchor(food,
+					mgl32.Vec2{hwidth/2 - iconAdvance*float32(i), y},
+					mgl32.Vec2{iconWidth, iconWidth},
+					Anchor{Horizontal: 1, Vertical: -1},
+				)
+			}
+		}
 	}
 }
+
+// Renders the screen shown after dying
+func RenderRespawnScreen() {
+	renderers.TintScreen(mgl32.Vec4{0.5, 0, 0, 0.5})
+	RenderText(mgl32.Vec2{0, 0.1}, "You died!", Anchor{Horizontal: 0, Vertical: 0})
+	RenderText(mgl32.Vec2{0, -0.1}, "Click to respawn", Anchor{Horizontal: 0, Vertical: 0})
+}
@@ -1,12 +1,7 @@
 package gui
 
 import (
-	"image"
-	"image/png"
-	"remakemc/client/assets"
 	"remakemc/client/renderers"
-
-	"github.com/go-gl/gl/v4.1-core/gl"
 )
 
 func Init() {
@@ -15,74 +10,31 @@ func Init() {
 	initFromAssets("hotbar.png", &hotbar)
 	initFromAssets("hotbar_selected.png", &hotbarSelected)
 	initFromAssets("inventory.png", &Inventory)
+	initFromAssets("chest.png", &Chest)
 	initFromAssets("slot_highlight.png", &SlotHighlight)
+	initFromAssets("tooltip.png", &tooltip)
+	initFromAssets("heart_full.png", &heartFull)
+	initFromAssets("heart_half.png", &heartHalf)
+	initFromAssets("heart_empty.png", &heartEmpty)
+	initFromAssets("food_full.png", &foodFull)
+	initFromAssets("food_half.png", &foodHalf)
+	initFromAssets("food_empty.png", &foodEmpty)
 }
 
 func initFromAssets(fileName string, target *renderers.GUIElem) {
-	// Read texture from embedded assets
-	f, err := assets.Files.Open(fileName)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-
-	i, err := png.Decode(f)
-	if err != nil {
-		panic(err)
-	}
-
-	// Generate texture
-	var tex uint32
-	gl.GenTextures(1, &tex)
-	gl.BindTexture(gl.TEXTURE_2D, tex)
-
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
-
-	// Assign texture data
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(i.Bounds().Dx()), int32(i.Bounds().Dy()), 0,
-		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(i.(*image.NRGBA).Pix))
-
-	// Create buffer for vertices
-	verts := renderers.GlBufferFrom([]float32{
-		0, 0, 1,
-		1, 0, 1,
-		1, 1, 1,
-		1, 1, 1,
-		0, 1, 1,
-		0, 0, 1,
-	})
-
-	// Create buffer for uvs
-	uvs := renderers.GlBufferFrom([]float32{
-		0, 1,
-		1, 1,
-		1, 0,
-		1, 0,
-		0, 0,
-		0, 1,
-	})
-
-	// Assign buffers to vertex array
-	var vao uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
-
-	gl.EnableVertexAttribArray(0)
-	gl.BindBuffer(gl.ARRAY_BUFFER, verts)
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 0, nil) // vec3
-
-	gl.EnableVertexAttribArray(1)
-	gl.BindBuffer(gl.ARRAY_BUFFER, uvs)
-	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 0, nil) // vec2
-
-	target.VAO = vao
-	target.Tex = tex
-	target.VertCount = 6
+	*target = renderers.NewGUIElemFromAsset(fileName)
 }
 
 var crosshair renderers.GUIElem
 var hotbar renderers.GUIElem
 var hotbarSelected renderers.GUIElem
 var Inventory renderers.GUIElem
+var Chest renderers.GUIElem
 var SlotHighlight renderers.GUIElem
+var tooltip renderers.GUIElem
+var heartFull renderers.GUIElem
+var heartHalf renderers.GUIElem
+var heartEmpty renderers.GUIElem
+var foodFull renderers.GUIElem
+var foodHalf renderers.GUIElem
+var foodEmpty renderers.GUIElem
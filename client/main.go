@@ -21,6 +21,7 @@ import (
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
 )
 
 var serverRead chan interface{}
@@ -30,10 +31,8 @@ var player *Player
 
 type meshDone struct {
 	position    core.Vec3
-	mesh        []float32
-	normals     []float32
-	uvs         []float32
-	lightLevels []float32
+	opaque      renderers.ChunkMesh
+	translucent renderers.ChunkMesh
 }
 
 var mouseOne = new(core.Debounced)
@@ -90,7 +89,7 @@ func Start() {
 		Chunks: make(map[core.Vec3]*core.Chunk),
 	}
 	for _, v := range chunks {
-		// dim.Entities = append(dim.Entities, v)
+		// dim.Entities.Add(v)
 		dim.Chunks[v.Position] = v
 	}
 
@@ -105,6 +104,8 @@ func Start() {
 	player.Azimuth = msg.Player.LookAzimuth
 	player.Elevation = msg.Player.LookElevation
 	player.Yaw = msg.Player.Yaw
+	core.SetPlayerGameMode(player, msg.GameMode)
+	player.Health = msg.Health
 
 	player.Inventory = new(container.Inventory)
 	player.Inventory.Init(true, msg.Player.EntityID)
@@ -113,7 +114,7 @@ func Start() {
 	renderers.Win.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
 	// renderers.Win.SetScrollCallback(player.ScrollCallback)
 
-	dim.Entities = append(dim.Entities, player)
+	dim.Entities.Add(player)
 
 	// Get all tickers
 	// var allTickers []core.Tickable
@@ -159,62 +160,47 @@ func Start() {
 		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 		gl.Enable(gl.DEBUG_OUTPUT)
 
-		// Process input and recalculate view matrix
-		if renderers.IsWindowFocused() && !containerOpen {
+		// Process input
+		if renderers.IsWindowFocused() && !containerOpen && !player.Dead() {
 			MouseSystem(dim, deltaTime)
 		}
-		view := mgl32.LookAtV(
-			player.CameraPos(),                       // Camera is at ... in World Space
-			player.CameraPos().Add(player.LookDir()), // and looks at
-			mgl32.Vec3{0, 1, 0},                      // Head is up
-		)
-
-		core.PhysicsSystem(dim, float32(deltaTime))
 
-		// See if we need to do a game tick
+		// See if we need to do a game tick. Movement only happens in ticks,
+		// the same as on the server.
 		collectedDelta += deltaTime
-		for ; collectedDelta >= 1.0/20; collectedDelta -= 1.0 / 20 {
-			// For some reason, this must be in a very specific order, or you can't jump
-			core.PhysicsTickSystem(dim)
-			PlayerSystem(dim)
+		for ; collectedDelta >= 1.0/core.TICK_RATE; collectedDelta -= 1.0 / core.TICK_RATE {
+			player.startTick()
+			in := PlayerSystem(dim)
+			core.PhysicsSystem(dim)
+			player.sendPosition(in)
 		}
 
-		// Mining
-		if !containerOpen && renderers.Win.GetMouseButton(glfw.MouseButton1) == glfw.Press && mouseOne.Invoke() {
-			core.TraceRay(player.LookDir(), player.CameraPos(), 16, func(v, h mgl32.Vec3) (stop bool) {
-				block := dim.GetBlockAt(core.NewVec3FromFloat(v))
-				if block.Type != nil {
-					serverWrite <- proto.BLOCK_DIG
-					serverWrite <- proto.BlockDig{
-						Position:      block.Position,
-						SubvoxelHit:   h,
-						FinishDigging: true, // TODO implement survival digging
-					}
+		// Show the player part of the way to their next position
+		player.tickProgress = float32(collectedDelta * core.TICK_RATE)
+		player.smoothCorrection(deltaTime)
+		core.LerpSystem(dim, entityClock)
 
-					return true
-				} else {
-					return false
-				}
-			})
-		} else if renderers.Win.GetMouseButton(glfw.MouseButton1) == glfw.Release {
-			mouseOne.Reset()
-		}
+		// Recalculate view matrix
+		view := mgl32.LookAtV(
+			player.CameraPos(),                       // Camera is at ... in World Space
+			player.CameraPos().Add(player.LookDir()), // and looks at
+			mgl32.Vec3{0, 1, 0},                      // Head is up
+		)
 
 		// Placing
-		if !containerOpen && renderers.Win.GetMouseButton(glfw.MouseButton2) == glfw.Press && mouseTwo.Invoke() {
-			core.TraceRay(player.LookDir(), player.CameraPos(), 16, func(v, h mgl32.Vec3) (stop bool) {
-				block := dim.GetBlockAt(core.NewVec3FromFloat(v))
-				if block.Type != nil {
-					serverWrite <- proto.BLOCK_INTERACTION
-					serverWrite <- proto.BlockInteraction{
-						Position:    block.Position,
-						SubvoxelHit: h,
-					}
-
-					return true
-				} else {
-					return false
+		if !containerOpen && !player.Dead() && !heldItemUsable() && renderers.Win.GetMouseButton(glfw.MouseButton2) == glfw.Press && mouseTwo.Invoke() {
+			trace := core.TraceBlocks
+			if heldItemPicksUpFluid() {
+				trace = core.TraceBlocksAndFluids
+			}
+			trace(dim, player.LookDir(), player.CameraPos(), core.BLOCK_REACH, func(block core.Block, h mgl32.Vec3) (stop bool) {
+				serverWrite <- proto.BLOCK_INTERACTION
+				serverWrite <- proto.BlockInteraction{
+					Position:    block.Position,
+					SubvoxelHit: h,
 				}
+
+				return true
 			})
 		} else if renderers.Win.GetMouseButton(glfw.MouseButton2) == glfw.Release {
 			mouseTwo.Reset()
@@ -224,29 +210,38 @@ func Start() {
 		renderers.RenderChunks(dim, view, player.Position)
 
 		// Find selector position and render
-		core.TraceRay(player.LookDir(), player.CameraPos(), 16, func(v, _ mgl32.Vec3) (stop bool) {
-			block := dim.GetBlockAt(core.NewVec3FromFloat(v))
-			if block.Type != nil {
-				renderers.RenderSelector(core.NewVec3FromFloat(v).ToFloat(), view)
-				return true
-			} else {
-				return false
-			}
+		core.TraceBlocks(dim, player.LookDir(), player.CameraPos(), core.BLOCK_REACH, func(block core.Block, _ mgl32.Vec3) (stop bool) {
+			renderers.RenderSelector(block, view)
+			return true
 		})
 
 		// Render all entities
-		for _, v := range core.GetEntitiesSatisfying[interface {
+		for _, v := range core.Query[interface {
 			core.RenderFace
 			core.Entity
-		}](dim.Entities) {
+		}](&dim.Entities) {
 			r := v.GetRenderComp()
 			if r != nil {
 				r.RenderEntity(v, view)
 			}
 		}
 
+		// Render water and anything else which can be seen through, once
+		// everything behind it has been rendered
+		renderers.RenderTranslucentChunks(dim, view, player.Position)
+
 		// Render gui
-		gui.RenderGame(player.SelectedHotbarSlot, core.GetStacksFromSlots(player.Inventory.GetSlots()[:9]))
+		var health *core.HealthComp
+		var hunger *core.HungerComp
+		if player.GameMode.TakesDamage() {
+			health = &player.HealthComp
+			hunger = &player.HungerComp
+		}
+		gui.RenderGame(player.SelectedHotbarSlot, core.GetStacksFromSlots(player.Inventory.GetSlots()[:9]), health, hunger)
+
+		// Mining and using items, which render their progress over the game
+		DigSystem(dim, deltaTime)
+		UseItemSystem(deltaTime)
 
 		gui.RenderText(
 			mgl32.Vec2{1, 1},
@@ -259,6 +254,10 @@ func Start() {
 			openContainer.Render()
 		}
 
+		if player.Dead() {
+			ProcessRespawnScreen()
+		}
+
 		// Update window
 		glfw.PollEvents()
 		renderers.Win.SwapBuffers()
@@ -288,84 +287,95 @@ func Start() {
 					for _, v := range chunks {
 						go func(c *core.Chunk) {
 							dim.Lock.RLock()
-							mesh, normals, uvs, lightLevels := renderers.MakeChunkMesh(dim, c.Position)
+							opaque, translucent := renderers.MakeChunkMesh(dim, c.Position)
 							dim.Lock.RUnlock()
-							serverRead <- meshDone{position: c.Position, mesh: mesh, normals: normals, uvs: uvs, lightLevels: lightLevels}
+							serverRead <- meshDone{position: c.Position, opaque: opaque, translucent: translucent}
 						}(v)
 					}
 
 				// Special internal event used to transfer mesh data generated in thread
 				case meshDone:
 					c := dim.Chunks[msg.position]
-					renderers.MakeChunkVAO(c, msg.mesh, msg.normals, msg.uvs, msg.lightLevels)
-
-					// case proto.EntityCreate:
-					// 	e := &core.Entity{
-					// 		ID:            msg.EntityID,
-					// 		Position:      msg.Position,
-					// 		AABB:          msg.AABB,
-					// 		EntityType:    msg.EntityType,
-					// 		Yaw:           msg.Yaw,
-					// 		Pitch:         msg.Pitch,
-					// 		LookAzimuth:   msg.LookAzimuth,
-					// 		LookElevation: msg.LookElevation,
-					// 	}
-					// 	typ := core.EntityRegistry[msg.EntityType]
-					// 	e.IsBlock = typ.IsBlock
-
-					// 	if !e.IsBlock {
-					// 		e.Lerp = true
-					// 		e.NewLerp(msg.Position)
-					// 	}
-					// 	dim.Entities = append(dim.Entities, e)
-
-					// case proto.EntityDelete:
-					// 	for k, v := range dim.Entities {
-					// 		if v.ID == uuid.UUID(msg) {
-					// 			dim.Entities = append(dim.Entities[:k], dim.Entities[k+1:]...)
-					// 			break
-					// 		}
-					// 	}
-
-					// case proto.EntityPosition:
-					// 	if msg.EntityID == player.ID {
-					// 		// Update the player's position absolutely.
-					// 		// Only happens when server thinks divergence is too high.
-					// 		// Will cause a rubberband
-					// 		player.Position = msg.Position
-					// 	} else {
-					// 		// Find the entity by ID
-					// 		for _, v := range dim.Entities {
-					// 			if v.ID == msg.EntityID {
-					// 				// Update
-					// 				v.LookAzimuth = msg.LookAzimuth
-					// 				v.LookElevation = msg.LookElevation
-					// 				v.Position = msg.Position
-					// 				v.Yaw = msg.Yaw
-
-					// 				v.NewLerp(msg.Position)
-					// 			}
-					// 		}
-					// 	}
-
-					// case proto.BlockUpdate:
-					// 	dim.Lock.Lock()
-
-					// 	dim.SetBlockAt(core.Block{
-					// 		Position: msg.Position,
-					// 		Type:     core.BlockRegistry[msg.BlockType],
-					// 	})
-					// 	renderers.UpdateRequiredMeshes(dim, msg.Position)
-
-					// 	dim.Lock.Unlock()
-
-					// case proto.EntityEquipment:
-					// 	fmt.Println(msg.HeldItemType)
-
-					// case proto.ContainerContents:
-					// 	// TODO Support non-inventories
-					// 	core.SetSlotsFromStacks(msg.Slots, player.Inventory.GetSlots())
-					// 	player.Inventory.SetFloating(msg.FloatingStack)
+					renderers.MakeChunkVAO(c, msg.opaque, msg.translucent)
+
+				case proto.EntityCreate:
+					if e := newRemoteEntity(msg); e != nil {
+						if r, ok := e.(core.RenderFace); ok {
+							r.RenderInit()
+						}
+						dim.Entities.Add(e)
+					}
+
+				case proto.EntityDelete:
+					deleteEntity(dim, uuid.UUID(msg))
+
+				case proto.EntityPosition:
+					updateEntityPosition(dim, msg)
+
+				case proto.BlockUpdate:
+					dim.Lock.Lock()
+
+					dim.SetBlockAt(core.Block{
+						Position: msg.Position,
+						Type:     core.BlockRegistry[msg.BlockType],
+					})
+					renderers.UpdateRequiredMeshes(dim, msg.Position)
+
+					dim.Lock.Unlock()
+
+				case proto.EntityMetadata:
+					updateEntityMetadata(dim, msg)
+
+				case proto.ContainerContents:
+					// The server's contents replace any predictions
+					containerRevision = msg.Revision
+					if msg.EntityID == player.ID {
+						core.SetSlotsFromStacks(msg.Slots, player.Inventory.GetSlots())
+						player.Inventory.SetFloating(msg.FloatingStack)
+					} else if containerOpen && openContainer.GetEntityID() == msg.EntityID {
+						core.SetSlotsFromStacks(msg.Slots, openContainer.GetSlots())
+						openContainer.SetFloating(msg.FloatingStack)
+					}
+
+				case proto.ContainerOpen:
+					containerRevision = msg.Revision
+					if c := NewContainerView(msg.ContainerType, msg.EntityID); c != nil {
+						core.SetSlotsFromStacks(msg.Slots, c.GetSlots())
+						c.SetFloating(msg.FloatingStack)
+						OpenContainer(c)
+					}
+
+				case proto.ContainerClose:
+					if containerOpen && openContainer.GetEntityID() == uuid.UUID(msg) {
+						hideContainer()
+					}
+
+				case proto.PlayerHealth:
+					setHealth(msg)
+
+				case proto.PlayerRespawn:
+					respawn(msg)
+
+				case proto.Ping:
+					// Answered from here, rather than when it arrives, so the
+					// time until the next frame is included
+					serverWrite <- proto.PONG
+					serverWrite <- proto.Pong(msg)
+
+				case proto.PlayerKnockback:
+					player.knockback(msg)
+
+				case proto.PlayerHunger:
+					player.Food = msg.Food
+					player.Saturation = msg.Saturation
+
+				case proto.PlayerGameMode:
+					core.SetPlayerGameMode(player, core.GameMode(msg))
+
+					// The server has already returned any floating stack
+					if containerOpen && openContainer.GetEntityID() == container.CreativeID {
+						hideContainer()
+					}
 				}
 			default:
 				break outer
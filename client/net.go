@@ -77,13 +77,13 @@ func readFromNet(serverRead chan interface{}) {
 			}
 			serverRead <- data
 
-		// case proto.ENTITY_EQUIPMENT:
-		// 	var data proto.EntityEquipment
-		// 	err = d.Decode(&data)
-		// 	if err != nil {
-		// 		panic(err)
-		// 	}
-		// 	serverRead <- data
+		case proto.ENTITY_METADATA:
+			var data proto.EntityMetadata
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
 
 		case proto.CONTAINER_CONTENTS:
 			var data proto.ContainerContents
@@ -93,6 +93,70 @@ func readFromNet(serverRead chan interface{}) {
 			}
 			serverRead <- data
 
+		case proto.CONTAINER_OPEN:
+			var data proto.ContainerOpen
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
+
+		case proto.CONTAINER_CLOSE:
+			var data proto.ContainerClose
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
+
+		case proto.PLAYER_GAME_MODE:
+			var data proto.PlayerGameMode
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
+
+		case proto.PLAYER_HEALTH:
+			var data proto.PlayerHealth
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
+
+		case proto.PING:
+			var data proto.Ping
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
+
+		case proto.PLAYER_RESPAWN:
+			var data proto.PlayerRespawn
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
+
+		case proto.PLAYER_HUNGER:
+			var data proto.PlayerHunger
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
+
+		case proto.PLAYER_KNOCKBACK:
+			var data proto.PlayerKnockback
+			err = d.Decode(&data)
+			if err != nil {
+				panic(err)
+			}
+			serverRead <- data
+
 		default:
 			panic("unknown packet type")
 		}
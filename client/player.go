@@ -17,29 +17,44 @@ type Player struct {
 	core.PositionComp
 	core.PhysicsComp
 	core.LookComp
+	core.HealthComp
+	core.HungerComp
+	core.MovementComp
 
-	Sprinting bool
-	Sneaking  bool
-
-	Speed           float64
 	MouseSensitivty float64
 
 	Inventory          *container.Inventory
 	SelectedHotbarSlot int
+
+	// The ticks of movement the server may still correct. See reconcile
+	inputSeq         int
+	history          []predictedTick
+	pendingKnockback []proto.PlayerKnockback
+	// How far the camera is from the player, while a correction is smoothed out
+	correction mgl32.Vec3
+
+	// The position at the start of the tick, and how far through the tick
+	// the frame is, from 0 to 1, to show the player between ticks
+	prevPosition mgl32.Vec3
+	tickProgress float32
 }
 
 func NewPlayer(position mgl32.Vec3, entityID uuid.UUID) *Player {
 	p := &Player{
-		Speed: 1,
 		EntityBase: core.EntityBase{
 			ID: entityID,
 		},
 		PhysicsComp: core.PhysicsComp{
-			AABB: mgl32.Vec3{0.6, 1.8, 0.6},
+			AABB:       core.PLAYER_AABB,
+			StepHeight: core.DEFAULT_STEP_HEIGHT,
 		},
 		PositionComp: core.PositionComp{
 			Position: position,
 		},
+		HealthComp:   core.NewHealthComp(core.PLAYER_MAX_HEALTH),
+		HungerComp:   core.NewHungerComp(),
+		MovementComp: core.NewMovementComp(),
+		prevPosition: position,
 	}
 	return p
 }
@@ -50,20 +65,17 @@ func (p *Player) GetTypeName() string {
 
 // The position of the camera
 func (p *Player) CameraPos() mgl32.Vec3 {
+	pos := p.renderPosition().Add(p.correction)
 	if p.Sneaking {
-		return p.Position.Add(mgl32.Vec3{0.3, 1.34, 0.3})
+		return pos.Add(core.PLAYER_SNEAKING_EYE_OFFSET)
 	}
 
-	return p.Position.Add(mgl32.Vec3{0.3, 1.62, 0.3})
+	return pos.Add(core.PLAYER_EYE_OFFSET)
 }
 
 // The direction the player is looking
 func (p *Player) LookDir() mgl32.Vec3 {
-	return mgl32.Vec3{
-		float32(math.Cos(p.Elevation) * math.Sin(p.Azimuth)),
-		float32(math.Sin(p.Elevation)),
-		float32(math.Cos(p.Elevation) * math.Cos(p.Azimuth)),
-	}
+	return core.LookDirection(p.Azimuth, p.Elevation)
 }
 
 // The direction the player would move if they pressed W
@@ -79,26 +91,33 @@ func (p *Player) RightVec() mgl32.Vec3 {
 	}
 }
 
-func (p *Player) SetSneaking(b bool) {
-	p.Sneaking = b
-	if b {
-		p.AABB[1] = 1.5
-	} else {
-		p.AABB[1] = 1.8
-	}
-}
+// The maximum time between presses of jump for them to toggle flying, in seconds
+const doubleJumpTime = 0.3
 
 var inventoryButton = new(core.Debounced)
 var escButton = new(core.Debounced)
+var backspaceButton = new(core.Debounced)
+var jumpButton = new(core.Debounced)
+var lastJumpTime float64
 
-// Process the keyboard input and physics for this tick
-func PlayerSystem(dim *core.Dimension) {
+// Process the keyboard input for this tick, returning the input which moved
+// the player. Should be run before the PhysicsSystem.
+func PlayerSystem(dim *core.Dimension) (in core.PlayerInput) {
 	// Get player
-	p := core.GetEntitiesSatisfying[*Player](dim.Entities)[0]
+	p := core.Query[*Player](&dim.Entities)[0]
+	in = core.PlayerInput{Idle: true}
+
+	// The dead can't move
+	if p.Dead() {
+		core.ApplyPlayerInput(p, in)
+		return
+	}
 
 	// Inventory mode
 	if containerOpen {
-		if renderers.Win.GetKey(glfw.KeyE) == glfw.Press && inventoryButton.Invoke() {
+		// Typing in the container shouldn't close it
+		input, textInput := openContainer.(textInputContainer)
+		if !textInput && renderers.Win.GetKey(glfw.KeyE) == glfw.Press && inventoryButton.Invoke() {
 			CloseContainer()
 		} else if renderers.Win.GetKey(glfw.KeyE) == glfw.Release {
 			inventoryButton.Reset()
@@ -110,14 +129,16 @@ func PlayerSystem(dim *core.Dimension) {
 			escButton.Reset()
 		}
 
-		// We still need to calculate drag
-		slipperiness := 1.0
-		if p.OnGround() {
-			slipperiness = 0.6
+		if textInput {
+			if renderers.Win.GetKey(glfw.KeyBackspace) == glfw.Press && backspaceButton.Invoke() {
+				input.InputBackspace()
+			} else if renderers.Win.GetKey(glfw.KeyBackspace) == glfw.Release {
+				backspaceButton.Reset()
+			}
 		}
-		p.Velocity[0] *= float32(0.91 * slipperiness)
-		p.Velocity[2] *= float32(0.91 * slipperiness)
 
+		// We still need to calculate drag
+		core.ApplyPlayerInput(p, in)
 		return
 	}
 
@@ -131,9 +152,14 @@ func PlayerSystem(dim *core.Dimension) {
 		}
 	}
 
-	// Open inventory
+	// Open inventory, or the creative palette
 	if renderers.Win.GetKey(glfw.KeyE) == glfw.Press && inventoryButton.Invoke() {
-		OpenContainer(player.Inventory)
+		switch p.GameMode {
+		case core.Survival:
+			OpenContainer(player.Inventory)
+		case core.Creative:
+			OpenContainer(newCreativeView())
+		}
 	} else if renderers.Win.GetKey(glfw.KeyE) == glfw.Release {
 		inventoryButton.Reset()
 	}
@@ -142,127 +168,38 @@ func PlayerSystem(dim *core.Dimension) {
 		escButton.Reset()
 	}
 
-	// Jump
-	var jumping bool
-	if renderers.Win.GetKey(glfw.KeySpace) == glfw.Press && p.OnGround() {
-		// TODO Add timeout to next jump
-		p.Velocity[1] = 8.4
-		jumping = true
-		serverWrite <- proto.PLAYER_JUMP
-	}
-
-	// Sneak
-	if renderers.Win.GetKey(glfw.KeyLeftShift) == glfw.Press {
-		// Nested for a reason
-		if !p.Sneaking {
-			p.SetSneaking(true)
-			serverWrite <- proto.PLAYER_SNEAKING
-			serverWrite <- proto.PlayerSneaking(true)
-		}
-	} else if p.Sneaking {
-		p.SetSneaking(false)
-		serverWrite <- proto.PLAYER_SNEAKING
-		serverWrite <- proto.PlayerSneaking(false)
-	}
-
-	// Sprint
-	if renderers.Win.GetKey(glfw.KeyLeftControl) == glfw.Press && !p.Sprinting {
-		p.Sprinting = true
-		serverWrite <- proto.PLAYER_SPRINTING
-		serverWrite <- proto.PlayerSprinting(true)
-	}
-
-	var walkVec mgl32.Vec2
-
-	// Move forwards
-	if renderers.Win.GetKey(glfw.KeyW) == glfw.Press {
-		walkVec[0] += 1
-	}
-	// Move backwards
-	if renderers.Win.GetKey(glfw.KeyS) == glfw.Press {
-		walkVec[0] += -1
-	}
-	// Move right
-	if renderers.Win.GetKey(glfw.KeyD) == glfw.Press {
-		walkVec[1] += 1
-	}
-	// Move left
-	if renderers.Win.GetKey(glfw.KeyA) == glfw.Press {
-		walkVec[1] += -1
-	}
-
-	if (walkVec.X() == 0 || p.Sneaking) && p.Sprinting {
-		p.Sprinting = false
-		serverWrite <- proto.PLAYER_SPRINTING
-		serverWrite <- proto.PlayerSprinting(false)
-	}
-
-	// Procees horizontal velocity according to
-	// https://www.mcpk.wiki/wiki/Horizontal_Movement_Formulas
-	moveMult := 1.0
-	if p.Sprinting {
-		moveMult = 1.3
-	} else if p.Sneaking {
-		moveMult = 0.3
-	} else if walkVec.X() == 0 && walkVec.Y() == 0 {
-		moveMult = 0
-	}
-	if walkVec.X() == 0 || walkVec.Y() == 0 {
-		moveMult *= 0.98
-	} else if p.Sneaking {
-		moveMult *= 0.98 * math.Sqrt(2)
-	}
-
-	slipperiness := 1.0
-	if p.OnGround() {
-		slipperiness = 0.6
-	}
-
-	groundAccel := float32(moveMult*p.Speed*math.Pow(0.6/slipperiness, 3)) * 0.1 * 20
-	direction := p.Azimuth
-	if moveMult != 0 {
-		if walkVec.X() == 0 {
-			if walkVec.Y() < 0 {
-				direction += math.Pi / 2
-			} else {
-				direction -= math.Pi / 2
-			}
-		} else {
-			direction -= math.Atan(float64(walkVec.Y() / walkVec.X()))
-		}
-	}
-	if walkVec.X() < 0 {
-		direction += math.Pi
-	}
-
-	p.Velocity[0] *= float32(0.91 * slipperiness)
-	p.Velocity[2] *= float32(0.91 * slipperiness)
-
-	if jumping {
-		p.Velocity[0] += groundAccel * float32(math.Sin(direction))
-		p.Velocity[2] += groundAccel * float32(math.Cos(direction))
+	// The server works out jumping, sneaking and sprinting from the input
+	in = readInput(p)
+	core.ApplyPlayerInput(p, in)
+	return
+}
 
-		if p.Sprinting {
-			p.Velocity[0] += 0.2 * float32(math.Sin(p.Azimuth)) * 20 * 0.91 * 0.6
-			p.Velocity[2] += 0.2 * float32(math.Cos(p.Azimuth)) * 20 * 0.91 * 0.6
+// Reads the keys which move the player
+func readInput(p *Player) core.PlayerInput {
+	in := core.PlayerInput{
+		Forward: renderers.Win.GetKey(glfw.KeyW) == glfw.Press,
+		Back:    renderers.Win.GetKey(glfw.KeyS) == glfw.Press,
+		Right:   renderers.Win.GetKey(glfw.KeyD) == glfw.Press,
+		Left:    renderers.Win.GetKey(glfw.KeyA) == glfw.Press,
+		Jump:    renderers.Win.GetKey(glfw.KeySpace) == glfw.Press,
+		Sneak:   renderers.Win.GetKey(glfw.KeyLeftShift) == glfw.Press,
+		Sprint:  renderers.Win.GetKey(glfw.KeyLeftControl) == glfw.Press,
+		Azimuth: p.Azimuth,
+	}
+
+	// Double tapping jump toggles flying
+	if in.Jump && jumpButton.Invoke() {
+		now := glfw.GetTime()
+		if p.GameMode == core.Creative && now-lastJumpTime < doubleJumpTime {
+			in.ToggleFlying = true
+			now = 0
 		}
-
-	} else if p.OnGround() {
-		p.Velocity[0] += groundAccel * float32(math.Sin(direction))
-		p.Velocity[2] += groundAccel * float32(math.Cos(direction))
-
-	} else {
-		p.Velocity[0] += 0.02 * float32(moveMult*math.Sin(direction)*20)
-		p.Velocity[2] += 0.02 * float32(moveMult*math.Cos(direction)*20)
+		lastJumpTime = now
+	} else if !in.Jump {
+		jumpButton.Reset()
 	}
 
-	// serverWrite <- proto.PLAYER_POSITION
-	// serverWrite <- proto.PlayerPosition{
-	// 	Position:      p.Position,
-	// 	Yaw:           p.Yaw,
-	// 	LookAzimuth:   p.Azimuth,
-	// 	LookElevation: p.Elevation,
-	// }
+	return in
 }
 
 // func (p *Player) ScrollCallback(_ *glfw.Window, _, yoff float64) {
@@ -278,7 +215,7 @@ func PlayerSystem(dim *core.Dimension) {
 // Process the mouse input for this frame
 func MouseSystem(dim *core.Dimension, deltaT float64) {
 	// Get player
-	p := core.GetEntitiesSatisfying[*Player](dim.Entities)[0]
+	p := core.Query[*Player](&dim.Entities)[0]
 
 	// Get and reset cursor position
 	xpos, ypos := renderers.Win.GetCursorPos()
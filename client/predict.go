@@ -0,0 +1,181 @@
+package client
+
+import (
+	"math"
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The most ticks of inputs kept to replay, 5 seconds. Corrections for older
+// inputs move the player without replaying.
+const MAX_PREDICTION_HISTORY = 100
+
+// Corrections smaller than this are smoothed out, instead of jumping the player
+const MAX_SMOOTHED_CORRECTION = 2
+
+// How quickly corrections are smoothed out, in seconds. Most of the correction
+// is gone after this time.
+const CORRECTION_SMOOTHING_TIME = 0.1
+
+// Everything about the player which their movement changes
+type playerState struct {
+	Position  mgl32.Vec3
+	Physics   core.PhysicsComp
+	Flying    bool
+	Sneaking  bool
+	Sprinting bool
+}
+
+type predictedTick struct {
+	Seq   int
+	Input core.PlayerInput
+	// Knockback received from the server since the last tick, which was
+	// applied before it
+	Knockback []proto.PlayerKnockback
+	// The state after the tick
+	State playerState
+}
+
+func (p *Player) saveState() playerState {
+	return playerState{
+		Position:  p.Position,
+		Physics:   p.PhysicsComp,
+		Flying:    p.Flying,
+		Sneaking:  p.Sneaking,
+		Sprinting: p.Sprinting,
+	}
+}
+
+func (p *Player) loadState(s playerState) {
+	p.Position = s.Position
+	p.PhysicsComp = s.Physics
+	p.Flying = s.Flying
+	p.Sneaking = s.Sneaking
+	p.Sprinting = s.Sprinting
+}
+
+// Remembers where the player was before the tick, to show them moving
+// between ticks
+func (p *Player) startTick() {
+	p.prevPosition = p.Position
+}
+
+// Where the player is shown, between their positions at the last two ticks
+func (p *Player) renderPosition() mgl32.Vec3 {
+	return p.prevPosition.Add(p.Position.Sub(p.prevPosition).Mul(p.tickProgress))
+}
+
+// Tells the server the input of the tick, and where the player is after it,
+// whatever they're doing
+func (p *Player) sendPosition(in core.PlayerInput) {
+	in.Knockback = len(p.pendingKnockback)
+	seq := p.recordTick(in)
+
+	serverWrite <- proto.PLAYER_POSITION
+	serverWrite <- proto.PlayerMove{
+		PlayerPosition: proto.PlayerPosition{
+			Position:      p.Position,
+			Yaw:           p.Yaw,
+			LookAzimuth:   p.Azimuth,
+			LookElevation: p.Elevation,
+			Seq:           seq,
+		},
+		Input: in,
+	}
+}
+
+// Remembers the input of the tick which has just been simulated, and returns
+// its sequence number to send to the server
+func (p *Player) recordTick(in core.PlayerInput) int {
+	p.inputSeq++
+	p.history = append(p.history, predictedTick{
+		Seq:       p.inputSeq,
+		Input:     in,
+		Knockback: p.pendingKnockback,
+		State:     p.saveState(),
+	})
+	p.pendingKnockback = nil
+
+	if len(p.history) > MAX_PREDICTION_HISTORY {
+		p.history = p.history[len(p.history)-MAX_PREDICTION_HISTORY:]
+	}
+
+	return p.inputSeq
+}
+
+// Applies knockback from the server, which is also replayed with the inputs
+func (p *Player) knockback(k proto.PlayerKnockback) {
+	core.ApplyKnockback(&p.PhysicsComp, k.Direction, k.Speed)
+	p.pendingKnockback = append(p.pendingKnockback, k)
+}
+
+// Moves the player to where the server says they were after the input with
+// the sequence number, then replays the inputs since then. The difference from
+// where the player was predicted to be is smoothed out over a few frames.
+func (p *Player) reconcile(dim *core.Dimension, msg proto.EntityPosition) {
+	i := -1
+	for k, v := range p.history {
+		if v.Seq == msg.Seq {
+			i = k
+			break
+		}
+	}
+
+	if i == -1 {
+		// Too old to replay, such as after a teleport
+		p.Position = msg.Position
+		p.Velocity = msg.Velocity
+		p.resetPrediction()
+		dim.Entities.Moved(p)
+		return
+	}
+
+	predicted := p.history[len(p.history)-1].State.Position
+
+	// Rewind
+	state := p.history[i].State
+	state.Position = msg.Position
+	state.Physics.Velocity = msg.Velocity
+	p.loadState(state)
+	p.history = p.history[i:]
+	p.history[0].State = state
+
+	// Replay, in the same order as the ticks were run. Knockback arrives
+	// between ticks, so is applied first.
+	for k := 1; k < len(p.history); k++ {
+		h := &p.history[k]
+		for _, v := range h.Knockback {
+			core.ApplyKnockback(&p.PhysicsComp, v.Direction, v.Speed)
+		}
+		core.ApplyPlayerInput(p, h.Input)
+		core.StepBody(dim, p)
+		h.State = p.saveState()
+	}
+
+	// Carry on showing the player between the same ticks
+	offset := p.Position.Sub(predicted)
+	p.prevPosition = p.prevPosition.Add(offset)
+
+	if offset.Len() < MAX_SMOOTHED_CORRECTION {
+		p.correction = p.correction.Sub(offset)
+	} else {
+		p.correction = mgl32.Vec3{}
+	}
+}
+
+// Forgets the inputs waiting to be acknowledged, such as after respawning.
+// The player is shown where they are now, rather than moving from where they
+// were.
+func (p *Player) resetPrediction() {
+	p.prevPosition = p.Position
+	p.history = nil
+	p.pendingKnockback = nil
+	p.correction = mgl32.Vec3{}
+}
+
+// Shrinks the offset the player is shown at after a correction, every frame
+func (p *Player) smoothCorrection(deltaT float64) {
+	p.correction = p.correction.Mul(float32(math.Exp(-deltaT / CORRECTION_SMOOTHING_TIME)))
+}
@@ -61,7 +61,16 @@ func (a *Atlas) AddTexFromAssets(texname string) {
 		panic(err)
 	}
 
-	a.AddTex(i.(*image.RGBA), texname)
+	switch i := i.(type) {
+	case *image.RGBA:
+		a.AddTex(i, texname)
+	case *image.NRGBA:
+		// Textures with transparency, such as water. The atlas is drawn with
+		// straight alpha, so the pixels are used as they are.
+		a.AddTex(&image.RGBA{Pix: i.Pix, Stride: i.Stride, Rect: i.Rect}, texname)
+	default:
+		panic("unsupported texture format: " + texname)
+	}
 }
 
 // Finalize the atlas, rendering all textures onto one image
@@ -68,6 +68,38 @@ func (t BlockBasicSixTex) RenderFace(face core.BlockFace, pos mgl32.Vec3) (verts
 	return
 }
 
+// A block made of boxes, such as slabs and stairs, with the same texture on
+// every face. The block type should be Transparent, as the boxes don't fill
+// the block.
+// TODO Crop the texture to the box, rather than squashing it
+type BlockBoxesOneTex struct {
+	Tex   string
+	Boxes []core.BlockBox
+}
+
+func (t BlockBoxesOneTex) Init() {
+	BlockAtlas.AddTexFromAssets(t.Tex)
+}
+
+func (t BlockBoxesOneTex) RenderFace(face core.BlockFace, pos mgl32.Vec3) (verts, normals, uvs []float32) {
+	atlasStart, atlasEnd := BlockAtlas.GetUV(t.Tex)
+
+	// TODO Faces inside the block are culled with the faces on its edge
+	for _, v := range t.Boxes {
+		// Fit the face of the whole block to the box
+		box := make([]float32, len(faceVertices[face]))
+		for i, c := range faceVertices[face] {
+			box[i] = v.Min[i%3] + c*(v.Max[i%3]-v.Min[i%3])
+		}
+
+		verts = append(verts, makeFace(box, pos)...)
+		uvs = append(uvs, makeUVs(faceUVs[face], atlasStart, atlasEnd)...)
+	}
+	normals = MakeNormals(verts)
+
+	return
+}
+
 // Add a position to a face
 func makeFace(face []float32, pos mgl32.Vec3) []float32 {
 	newV := make([]float32, 3*6)
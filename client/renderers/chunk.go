@@ -124,6 +124,31 @@ void main() {
 }
 
 func RenderChunks(dim *core.Dimension, view mgl32.Mat4, playerPos mgl32.Vec3) {
+	useChunkProg(view)
+	eachRenderedChunk(dim, playerPos, func(c *core.Chunk) {
+		RenderChunk(c, view)
+	})
+}
+
+// Draws the Translucent blocks of the chunks, such as water. This must be done
+// after everything else has been drawn, so it can be seen through them.
+// TODO Sort the chunks from back to front
+func RenderTranslucentChunks(dim *core.Dimension, view mgl32.Mat4, playerPos mgl32.Vec3) {
+	useChunkProg(view)
+
+	// Surfaces can be seen from both sides, such as from under water, and
+	// don't hide each other
+	gl.Disable(gl.CULL_FACE)
+	gl.DepthMask(false)
+	eachRenderedChunk(dim, playerPos, func(c *core.Chunk) {
+		if c.TranslucentMeshLen != 0 {
+			drawChunkMesh(c, c.TranslucentVAO, c.TranslucentMeshLen)
+		}
+	})
+	gl.DepthMask(true)
+}
+
+func useChunkProg(view mgl32.Mat4) {
 	gl.UseProgram(chunkProg)
 	gl.Enable(gl.CULL_FACE)
 	gl.Enable(gl.DEPTH_TEST)
@@ -142,8 +167,10 @@ func RenderChunks(dim *core.Dimension, view mgl32.Mat4, playerPos mgl32.Vec3) {
 	gl.ActiveTexture(gl.TEXTURE0)
 	gl.BindTexture(gl.TEXTURE_2D, chunkTex)
 	gl.Uniform1i(chunkTUniform, int32(chunkTex-1))
+}
 
-	i := 0
+// Calls back for every chunk within the render distance of the player
+func eachRenderedChunk(dim *core.Dimension, playerPos mgl32.Vec3, fn func(c *core.Chunk)) {
 	chunkPos := core.NewVec3(
 		core.FlooredDivision(core.FloorFloat32(playerPos.X()), 16)*16,
 		0,
@@ -156,8 +183,7 @@ func RenderChunks(dim *core.Dimension, view mgl32.Mat4, playerPos mgl32.Vec3) {
 				if c == nil {
 					panic(fmt.Sprint("nil chunk at", chunkPos.Add(core.NewVec3(x, y, z))))
 				}
-				i++
-				RenderChunk(c, view)
+				fn(c)
 			}
 		}
 	}
@@ -172,28 +198,38 @@ func RenderChunk(c *core.Chunk, view mgl32.Mat4) {
 		return
 	}
 
+	drawChunkMesh(c, c.VAO, c.MeshLen)
+}
+
+func drawChunkMesh(c *core.Chunk, vao uint32, meshLen int) {
 	// Translate chunk into position
 	p := c.Position.ToFloat()
 	model := mgl32.Translate3D(p[0], p[1], p[2])
 	gl.UniformMatrix4fv(chunkMUniform, 1, false, &model[0])
 
 	// Draw
-	gl.BindVertexArray(c.VAO)
-	gl.DrawArrays(gl.TRIANGLES, 0, int32(c.MeshLen))
+	gl.BindVertexArray(vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(meshLen))
 }
 
 func MakeChunkMeshAndVAO(d *core.Dimension, chunk *core.Chunk) {
-	mesh, normals, uvs, lightLevels := MakeChunkMesh(d, chunk.Position)
-	MakeChunkVAO(chunk, mesh, normals, uvs, lightLevels)
+	opaque, translucent := MakeChunkMesh(d, chunk.Position)
+	MakeChunkVAO(chunk, opaque, translucent)
 }
 
-func MakeChunkVAO(chunk *core.Chunk, mesh, normals, uvs, lightLevels []float32) {
-	var vao uint32
+func MakeChunkVAO(chunk *core.Chunk, opaque, translucent ChunkMesh) {
+	chunk.VAO, chunk.MeshLen = makeMeshVAO(chunk, opaque)
+	chunk.TranslucentVAO, chunk.TranslucentMeshLen = makeMeshVAO(chunk, translucent)
+}
+
+func makeMeshVAO(chunk *core.Chunk, m ChunkMesh) (vao uint32, meshLen int) {
+	mesh, normals, uvs, lightLevels := m.Verts, m.Normals, m.UVs, m.LightLevels
+
 	gl.GenVertexArrays(1, &vao)
 	gl.BindVertexArray(vao)
 
 	if len(mesh) == 0 {
-		return
+		return vao, 0
 	}
 
 	buf := GlBufferFrom(mesh)
@@ -220,12 +256,12 @@ func MakeChunkVAO(chunk *core.Chunk, mesh, normals, uvs, lightLevels []float32)
 	gl.BindBuffer(gl.ARRAY_BUFFER, buf)
 	gl.VertexAttribPointer(3, 1, gl.FLOAT, false, 0, nil) // float
 
-	chunk.VAO = vao
-	chunk.MeshLen = len(mesh)
+	return vao, len(mesh)
 }
 
 func FreeChunk(c *core.Chunk) {
 	gl.DeleteVertexArrays(1, &c.VAO)
+	gl.DeleteVertexArrays(1, &c.TranslucentVAO)
 	if len(c.VertexBuffers) > 0 {
 		gl.DeleteBuffers(int32(len(c.VertexBuffers)), &c.VertexBuffers[0])
 	}
@@ -301,7 +337,26 @@ func UpdateRequiredMeshes(dim *core.Dimension, updatePos core.Vec3) {
 	}
 }
 
-func MakeChunkMesh(d *core.Dimension, chunkPos core.Vec3) (verts, normals, uvs, lightLevels []float32) {
+// The vertices of blocks in a chunk, and their attributes
+type ChunkMesh struct {
+	Verts, Normals, UVs, LightLevels []float32
+}
+
+// Whether the face of the block next to the neighbour can be seen
+func showFace(b, neighbour core.Block) bool {
+	if neighbour.Type == nil {
+		return true
+	}
+	// The inside of fluids can't be seen
+	if b.Type.Fluid != nil && neighbour.Type.Fluid == b.Type.Fluid {
+		return false
+	}
+	return neighbour.Type.Transparent
+}
+
+// Makes the meshes of the blocks in the chunk, with Translucent blocks in a
+// separate mesh to be drawn after everything else
+func MakeChunkMesh(d *core.Dimension, chunkPos core.Vec3) (opaque, translucent ChunkMesh) {
 	chunkGuess := d.Chunks[chunkPos]
 
 	for x := 0; x < 16; x++ {
@@ -314,76 +369,29 @@ func MakeChunkMesh(d *core.Dimension, chunkPos core.Vec3) (verts, normals, uvs,
 					continue
 				}
 
-				// Top
-				top := d.GetBlockAtOptimised(global.Add(core.Vec3{Y: 1}), chunkGuess)
-				if top.Type == nil || top.Type.Transparent {
-					face := core.FaceTop
-					ll, flip := MakeLightLevelsForFace(d, global, face)
-					v, n, u := b.Type.RenderType.RenderFace(face, local.ToFloat())
-					verts = append(verts, flipIfTrue(v, flip, face, 3)...)
-					normals = append(normals, flipIfTrue(n, flip, face, 3)...)
-					uvs = append(uvs, flipIfTrue(u, flip, face, 2)...)
-					lightLevels = append(lightLevels, flipIfTrue(ll, flip, face, 1)...)
-				}
-
-				// Bottom
-				bottom := d.GetBlockAtOptimised(global.Add(core.Vec3{Y: -1}), chunkGuess)
-				if bottom.Type == nil || bottom.Type.Transparent {
-					face := core.FaceBottom
-					ll, flip := MakeLightLevelsForFace(d, global, face)
-					v, n, u := b.Type.RenderType.RenderFace(face, local.ToFloat())
-					verts = append(verts, flipIfTrue(v, flip, face, 3)...)
-					normals = append(normals, flipIfTrue(n, flip, face, 3)...)
-					uvs = append(uvs, flipIfTrue(u, flip, face, 2)...)
-					lightLevels = append(lightLevels, flipIfTrue(ll, flip, face, 1)...)
-				}
-
-				// Left
-				left := d.GetBlockAtOptimised(global.Add(core.Vec3{X: -1}), chunkGuess)
-				if left.Type == nil || left.Type.Transparent {
-					face := core.FaceLeft
-					ll, flip := MakeLightLevelsForFace(d, global, face)
-					v, n, u := b.Type.RenderType.RenderFace(face, local.ToFloat())
-					verts = append(verts, flipIfTrue(v, flip, face, 3)...)
-					normals = append(normals, flipIfTrue(n, flip, face, 3)...)
-					uvs = append(uvs, flipIfTrue(u, flip, face, 2)...)
-					lightLevels = append(lightLevels, flipIfTrue(ll, flip, face, 1)...)
+				m := &opaque
+				if b.Type.Translucent {
+					m = &translucent
 				}
 
-				// Right
-				right := d.GetBlockAtOptimised(global.Add(core.Vec3{X: 1}), chunkGuess)
-				if right.Type == nil || right.Type.Transparent {
-					face := core.FaceRight
-					ll, flip := MakeLightLevelsForFace(d, global, face)
-					v, n, u := b.Type.RenderType.RenderFace(face, local.ToFloat())
-					verts = append(verts, flipIfTrue(v, flip, face, 3)...)
-					normals = append(normals, flipIfTrue(n, flip, face, 3)...)
-					uvs = append(uvs, flipIfTrue(u, flip, face, 2)...)
-					lightLevels = append(lightLevels, flipIfTrue(ll, flip, face, 1)...)
-				}
+				for _, face := range []core.BlockFace{
+					core.FaceTop, core.FaceBottom, core.FaceLeft, core.FaceRight, core.FaceFront, core.FaceBack,
+				} {
+					neighbour := d.GetBlockAtOptimised(global.Add(core.FaceDirection[face]), chunkGuess)
+					if !showFace(b, neighbour) {
+						continue
+					}
 
-				// Front
-				front := d.GetBlockAtOptimised(global.Add(core.Vec3{Z: 1}), chunkGuess)
-				if front.Type == nil || front.Type.Transparent {
-					face := core.FaceFront
 					ll, flip := MakeLightLevelsForFace(d, global, face)
 					v, n, u := b.Type.RenderType.RenderFace(face, local.ToFloat())
-					verts = append(verts, flipIfTrue(v, flip, face, 3)...)
-					normals = append(normals, flipIfTrue(n, flip, face, 3)...)
-					uvs = append(uvs, flipIfTrue(u, flip, face, 2)...)
-					lightLevels = append(lightLevels, flipIfTrue(ll, flip, face, 1)...)
-				}
 
-				// Back
-				back := d.GetBlockAtOptimised(global.Add(core.Vec3{Z: -1}), chunkGuess)
-				if back.Type == nil || back.Type.Transparent {
-					face := core.FaceBack
-					ll, flip := MakeLightLevelsForFace(d, global, face)
-					v, n, u := b.Type.RenderType.RenderFace(face, local.ToFloat())
-					verts = append(verts, flipIfTrue(v, flip, face, 3)...)
-					normals = append(normals, flipIfTrue(n, flip, face, 3)...)
-					uvs = append(uvs, flipIfTrue(u, flip, face, 2)...)
-					lightLevels = append(lightLevels, flipIfTrue(ll, flip, face, 1)...)
+					// Blocks made of boxes have a face for each box
+					for i := 0; i < len(v)/18; i++ {
+						m.Verts = append(m.Verts, flipIfTrue(v[i*18:(i+1)*18], flip, face, 3)...)
+						m.Normals = append(m.Normals, flipIfTrue(n[i*18:(i+1)*18], flip, face, 3)...)
+						m.UVs = append(m.UVs, flipIfTrue(u[i*12:(i+1)*12], flip, face, 2)...)
+						m.LightLevels = append(m.LightLevels, flipIfTrue(ll, flip, face, 1)...)
+					}
 				}
 			}
 		}
@@ -1,10 +1,12 @@
 package renderers
 
 import (
+	"math"
 	"remakemc/core"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
 )
 
 var ReusableShaders = make(map[string](func() *Shader))
@@ -21,22 +23,38 @@ func initEntity() {
 	}
 
 	for _, v := range core.EntityRegistry {
-		if r, ok := v.(core.RenderFace); ok {
+		if r, ok := v.New(uuid.Nil).(core.RenderFace); ok {
 			r.RenderInit()
 		}
 	}
 }
 
+// How far a held item moves down and forward while swinging
+const SWING_DISTANCE = 0.4
+
 type TestEntityRenderer struct {
 	Vertices []float32
 	Shader   string // index into ReusableShaders
 
+	// The height of the vertices. If set, the entity is squashed to the
+	// height of its AABB, such as when a player is sneaking.
+	Height float32
+
+	// Drawn at the offset from the entity's position when it is holding an
+	// item, if set
+	// TODO Render the texture of the held item
+	HeldItem   *TestEntityRenderer
+	HandOffset mgl32.Vec3
+
 	shader *Shader
 	vao    uint32
 }
 
 func (d *TestEntityRenderer) Init() {
 	d.shader = compiledShaders[d.Shader]
+	if d.HeldItem != nil {
+		d.HeldItem.Init()
+	}
 
 	// Make entity VAO
 	verts := GlBufferFrom(d.Vertices)
@@ -55,6 +73,40 @@ func (d *TestEntityRenderer) Init() {
 }
 
 func (d *TestEntityRenderer) RenderEntity(e core.Entity, view mgl32.Mat4) {
+	pos := *e.(core.PositionFace).GetPosition()
+	scale := float32(1)
+	if p, ok := e.(core.PhysicsFace); ok && d.Height != 0 {
+		scale = p.GetPhysicsComp().AABB.Y() / d.Height
+	}
+
+	tint := mgl32.Vec3{1, 1, 1}
+	m, hasMetadata := e.(core.MetadataFace)
+	if hasMetadata && m.GetMetadataComp().Hurt() {
+		tint = mgl32.Vec3{1, 0.4, 0.4}
+	}
+
+	model := mgl32.Translate3D(pos[0], pos[1], pos[2]).Mul4(mgl32.Scale3D(1, scale, 1))
+	d.draw(model, tint, view)
+
+	if d.HeldItem == nil || !hasMetadata || m.GetMetadataComp().Metadata.HeldItem == "" {
+		return
+	}
+
+	hand := pos.Add(mgl32.Vec3{d.HandOffset.X(), d.HandOffset.Y() * scale, d.HandOffset.Z()})
+	if p, ok := m.GetMetadataComp().SwingProgress(); ok {
+		var look mgl32.Vec3
+		if l, ok := e.(core.LookFace); ok {
+			look = core.LookDirection(l.GetLookComp().Azimuth, l.GetLookComp().Elevation)
+		}
+
+		// Move out and back
+		swing := float32(math.Sin(float64(p) * math.Pi))
+		hand = hand.Add(look.Sub(mgl32.Vec3{0, 1, 0}).Mul(swing * SWING_DISTANCE))
+	}
+	d.HeldItem.draw(mgl32.Translate3D(hand[0], hand[1], hand[2]), tint, view)
+}
+
+func (d *TestEntityRenderer) draw(model mgl32.Mat4, tint mgl32.Vec3, view mgl32.Mat4) {
 	gl.UseProgram(d.shader.Program)
 	gl.Enable(gl.DEPTH_TEST)
 	gl.DepthFunc(gl.LEQUAL)
@@ -64,10 +116,8 @@ func (d *TestEntityRenderer) RenderEntity(e core.Entity, view mgl32.Mat4) {
 	gl.UniformMatrix4fv(d.shader.Uniforms["projection"], 1, false, &projection[0])
 	gl.UniformMatrix4fv(d.shader.Uniforms["view"], 1, false, &view[0])
 
-	// Translate entity into position
-	pos := e.(core.PositionFace).GetPosition()
-	model := mgl32.Translate3D(pos[0], pos[1], pos[2])
 	gl.UniformMatrix4fv(d.shader.Uniforms["model"], 1, false, &model[0])
+	gl.Uniform3fv(d.shader.Uniforms["tint"], 1, &tint[0])
 
 	// Draw
 	gl.BindVertexArray(d.vao)
@@ -1,6 +1,10 @@
 package renderers
 
 import (
+	"image"
+	"image/png"
+	"remakemc/client/assets"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -75,6 +79,72 @@ void main() {
 	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 0, nil) // vec3
 }
 
+// Creates a GUI element from a PNG in the assets, which must have an alpha channel
+func NewGUIElemFromAsset(fileName string) GUIElem {
+	// Read texture from embedded assets
+	f, err := assets.Files.Open(fileName)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	i, err := png.Decode(f)
+	if err != nil {
+		panic(err)
+	}
+
+	// Generate texture
+	var tex uint32
+	gl.GenTextures(1, &tex)
+	gl.BindTexture(gl.TEXTURE_2D, tex)
+
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+
+	// Assign texture data
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(i.Bounds().Dx()), int32(i.Bounds().Dy()), 0,
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(i.(*image.NRGBA).Pix))
+
+	// Create buffer for vertices
+	verts := GlBufferFrom([]float32{
+		0, 0, 1,
+		1, 0, 1,
+		1, 1, 1,
+		1, 1, 1,
+		0, 1, 1,
+		0, 0, 1,
+	})
+
+	// Create buffer for uvs
+	uvs := GlBufferFrom([]float32{
+		0, 1,
+		1, 1,
+		1, 0,
+		1, 0,
+		0, 0,
+		0, 1,
+	})
+
+	// Assign buffers to vertex array
+	var vao uint32
+	gl.GenVertexArrays(1, &vao)
+	gl.BindVertexArray(vao)
+
+	gl.EnableVertexAttribArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, verts)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 0, nil) // vec3
+
+	gl.EnableVertexAttribArray(1)
+	gl.BindBuffer(gl.ARRAY_BUFFER, uvs)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 0, nil) // vec2
+
+	return GUIElem{
+		VAO:       vao,
+		Tex:       tex,
+		VertCount: 6,
+	}
+}
+
 func RenderGUIElement(e GUIElem, start, end mgl32.Vec2) {
 	gl.UseProgram(guiProg)
 
@@ -89,3 +89,18 @@ func (t *ItemFromBlock) RenderItem(i *core.ItemType, start mgl32.Vec2, end mgl32
 	gl.BindVertexArray(t.vao)
 	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(t.verts)/3))
 }
+
+// An item rendered as a flat texture, such as food
+type ItemFlat struct {
+	Tex string // the name of the texture in the assets, without .png
+
+	elem GUIElem
+}
+
+func (t *ItemFlat) Init() {
+	t.elem = NewGUIElemFromAsset(t.Tex + ".png")
+}
+
+func (t *ItemFlat) RenderItem(i *core.ItemType, start mgl32.Vec2, end mgl32.Vec2) {
+	RenderGUIElement(t.elem, start, end)
+}
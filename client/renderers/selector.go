@@ -1,6 +1,8 @@
 package renderers
 
 import (
+	"remakemc/core"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -99,7 +101,8 @@ void main() {
 	selectorMUniform = gl.GetUniformLocation(selectorProg, gl.Str("model\x00"))
 }
 
-func RenderSelector(pos mgl32.Vec3, view mgl32.Mat4) {
+// Outlines the selection boxes of the block
+func RenderSelector(b core.Block, view mgl32.Mat4) {
 	gl.UseProgram(selectorProg)
 	gl.Enable(gl.DEPTH_TEST)
 	gl.DepthFunc(gl.LEQUAL)
@@ -109,11 +112,15 @@ func RenderSelector(pos mgl32.Vec3, view mgl32.Mat4) {
 	gl.UniformMatrix4fv(selectorPUniform, 1, false, &projection[0])
 	gl.UniformMatrix4fv(selectorVUniform, 1, false, &view[0])
 
-	// Translate selector into position
-	model := mgl32.Translate3D(pos[0], pos[1], pos[2])
-	gl.UniformMatrix4fv(selectorMUniform, 1, false, &model[0])
-
-	// Draw
 	gl.BindVertexArray(selectorVao)
-	gl.DrawArrays(gl.LINES, 0, 12*2)
+	for _, v := range b.Type.GetSelectionBoxes() {
+		// Translate and scale selector to the box
+		min, max := v.At(b.Position)
+		size := max.Sub(min)
+		model := mgl32.Translate3D(min[0], min[1], min[2]).Mul4(mgl32.Scale3D(size[0], size[1], size[2]))
+		gl.UniformMatrix4fv(selectorMUniform, 1, false, &model[0])
+
+		// Draw
+		gl.DrawArrays(gl.LINES, 0, 12*2)
+	}
 }
@@ -34,6 +34,7 @@ void main() {
 #version 410
 
 uniform vec3 cameraPosition;
+uniform vec3 tint;
 
 in vec3 fragNormal;
 in vec3 fragVertex;
@@ -60,7 +61,7 @@ vec3 ApplyLight(vec3 surfaceColor, vec3 normal, vec3 surfacePos, vec3 surfaceToC
 void main() {
 	vec3 normal = normalize(transpose(inverse(mat3(fragModel))) * fragNormal);
 	vec3 surfacePos = vec3((fragModel * vec4(fragVertex, 1)).xyz);
-	vec4 surfaceColor = vec4(1.0);
+	vec4 surfaceColor = vec4(tint, 1.0);
 	vec3 surfaceToCamera = normalize(cameraPosition - surfacePos);
 
 	// Combine color from all the lights
@@ -84,6 +85,7 @@ void main() {
 		shad.Uniforms["projection"] = gl.GetUniformLocation(prog, gl.Str("projection\x00"))
 		shad.Uniforms["view"] = gl.GetUniformLocation(prog, gl.Str("view\x00"))
 		shad.Uniforms["model"] = gl.GetUniformLocation(prog, gl.Str("model\x00"))
+		shad.Uniforms["tint"] = gl.GetUniformLocation(prog, gl.Str("tint\x00"))
 
 		return &shad
 	}
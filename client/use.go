@@ -0,0 +1,90 @@
+package client
+
+import (
+	"fmt"
+	"remakemc/client/gui"
+	"remakemc/client/renderers"
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Whether the held item is being used, and for how long
+var usingItem bool
+var useSlot int
+var useTime float64
+
+// Whether the held item is used rather than placed
+func heldItemUsable() bool {
+	s := player.Inventory.GetSlots()[player.SelectedHotbarSlot].GetStack()
+	return !s.IsEmpty() && core.ItemRegistry[s.Item].UseDuration > 0
+}
+
+// Whether the held item is used on fluids rather than the blocks behind them,
+// such as empty buckets
+func heldItemPicksUpFluid() bool {
+	s := player.Inventory.GetSlots()[player.SelectedHotbarSlot].GetStack()
+	return !s.IsEmpty() && core.ItemRegistry[s.Item].PicksUpFluid
+}
+
+// Uses the held item while the right mouse button is held, such as eating.
+// The button must be held for the item's UseDuration.
+func UseItemSystem(deltaT float64) {
+	if renderers.Win.GetMouseButton(glfw.MouseButton2) == glfw.Release {
+		usingItem = false
+		mouseTwo.Reset()
+		return
+	}
+	if containerOpen || player.Dead() || !player.GameMode.CanInteract() || !heldItemUsable() {
+		usingItem = false
+		return
+	}
+
+	item := core.ItemRegistry[player.Inventory.GetSlots()[player.SelectedHotbarSlot].GetStack().Item]
+	if item.Food != nil && !player.CanEat() && player.GameMode.TakesDamage() {
+		usingItem = false
+		return
+	}
+	if item.Ammo != "" && player.GameMode.ConsumesItems() && !hasItem(item.Ammo) {
+		usingItem = false
+		return
+	}
+
+	// Start using again whenever the held item changes
+	if !usingItem || useSlot != player.SelectedHotbarSlot {
+		usingItem = true
+		useSlot = player.SelectedHotbarSlot
+		useTime = 0
+
+		serverWrite <- proto.ITEM_USE
+		serverWrite <- proto.ItemUse{}
+	}
+
+	useTime += deltaT
+	if useTime >= float64(item.UseDuration)/20 {
+		usingItem = false
+
+		serverWrite <- proto.ITEM_USE
+		serverWrite <- proto.ItemUse{FinishUsing: true}
+		return
+	}
+
+	// TODO Animate the held item instead
+	gui.RenderText(
+		mgl32.Vec2{0, -0.1},
+		fmt.Sprintf("%d%%", int(useTime/(float64(item.UseDuration)/20)*100)),
+		gui.Anchor{Horizontal: 0, Vertical: 1},
+	)
+}
+
+// Whether the player has any of the item in their inventory
+func hasItem(item string) bool {
+	for _, v := range player.Inventory.GetSlots() {
+		if s := v.GetStack(); !s.IsEmpty() && s.Item == item {
+			return true
+		}
+	}
+	return false
+}
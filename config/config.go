@@ -22,8 +22,12 @@ type AppConfig struct {
 	}
 
 	Server struct {
-		Address string
-		Port    int
+		Address         string
+		Port            int
+		DefaultGameMode string
+		PvP             bool
+		WorldDir        string
+		RandomTickSpeed int
 	}
 }
 
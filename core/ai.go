@@ -0,0 +1,270 @@
+package core
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// A Mob is an entity which moves around by itself, controlled by its goals
+type Mob interface {
+	Entity
+	PositionFace
+	PhysicsFace
+	LookFace
+	HealthFace
+	AIFace
+}
+
+type AIFace interface {
+	GetAIComp() *AIComp
+}
+
+// A Goal is a behaviour a mob can perform, such as wandering around or
+// attacking a player. Only one goal runs at a time.
+type Goal interface {
+	// Whether the goal should start, checked every tick while a less
+	// important goal is running
+	CanStart(m Mob, ctx *AIContext) bool
+	// Whether the goal should keep running
+	ShouldContinue(m Mob, ctx *AIContext) bool
+
+	Start(m Mob, ctx *AIContext)
+	Tick(m Mob, ctx *AIContext)
+	Stop(m Mob)
+}
+
+type AIComp struct {
+	// The goals of the mob, with the most important first. These aren't
+	// saved, so must be set by the New of the entity type.
+	Goals []Goal
+	// The speed the mob walks at, in m/s
+	Speed float32
+
+	current Goal
+
+	// Where the mob is walking to, set by the goals
+	moving       bool
+	moveTarget   mgl32.Vec3
+	moveSpeedMul float32
+
+	// The route to the move target, which is found again when the target
+	// moves to another block or a block on the route changes
+	path      *Path
+	pathGoal  Vec3
+	pathIndex int
+}
+
+func (a *AIComp) GetAIComp() *AIComp {
+	return a
+}
+
+// Makes the mob walk towards the position, at the multiple of its speed
+func (a *AIComp) MoveTo(pos mgl32.Vec3, speedMul float32) {
+	a.moving = true
+	a.moveTarget = pos
+	a.moveSpeedMul = speedMul
+}
+
+// You must lock Dim yourself
+func (a *AIComp) StopMoving() {
+	a.moving = false
+	a.path.Release()
+	a.path = nil
+}
+
+// Whether the mob is still walking to the position given to MoveTo
+func (a *AIComp) Moving() bool {
+	return a.moving
+}
+
+// A player which mobs can follow or attack
+type AITarget struct {
+	ID       uuid.UUID
+	Position mgl32.Vec3
+	HeldItem string
+	// Whether the player can be attacked, as they may be in creative
+	Attackable bool
+}
+
+// Everything the goals can see of the world
+type AIContext struct {
+	Dim     *Dimension
+	Players []AITarget
+	Rand    *rand.Rand
+
+	// Called when a mob attacks a player
+	Attack func(m Mob, target uuid.UUID, damage float32)
+}
+
+// Finds the closest player within the range which satisfies the filter
+func (ctx *AIContext) NearestPlayer(pos mgl32.Vec3, within float32, filter func(t AITarget) bool) (AITarget, bool) {
+	var nearest AITarget
+	var found bool
+	for _, v := range ctx.Players {
+		d := v.Position.Sub(pos).Len()
+		if d > within || (filter != nil && !filter(v)) {
+			continue
+		}
+
+		if !found || d < nearest.Position.Sub(pos).Len() {
+			nearest = v
+			found = true
+		}
+	}
+	return nearest, found
+}
+
+// The distance a mob must be from its move target to have arrived
+const arriveDistance = 0.5
+
+// Runs the goals of all mobs in the dimension, and makes them walk where their
+// goals want them to. Should be run every tick, before the physics.
+func AISystem(ctx *AIContext) {
+	for _, m := range Query[Mob](&ctx.Dim.Entities) {
+		a := m.GetAIComp()
+		if m.GetHealthComp().Dead() {
+			continue
+		}
+
+		selectGoal(m, ctx)
+		if a.current != nil {
+			a.current.Tick(m, ctx)
+		}
+
+		steer(m, ctx.Dim)
+	}
+}
+
+// Starts the most important goal that can start, if it is more important than
+// the running goal, and stops the running goal if it has finished
+func selectGoal(m Mob, ctx *AIContext) {
+	a := m.GetAIComp()
+
+	if a.current != nil && !a.current.ShouldContinue(m, ctx) {
+		a.current.Stop(m)
+		a.current = nil
+	}
+
+	for _, v := range a.Goals {
+		if v == a.current {
+			// Only more important goals can interrupt
+			break
+		}
+
+		if v.CanStart(m, ctx) {
+			if a.current != nil {
+				a.current.Stop(m)
+			}
+			a.current = v
+			v.Start(m, ctx)
+			break
+		}
+	}
+}
+
+// The furthest a mob will look below its move target for the ground to path to
+const pathGoalDrop = 4
+
+// Finds where the mob should walk to next to reach its move target, following
+// a path through the terrain. Once at the end of the path, the mob walks
+// straight to the target.
+func waypoint(m Mob, dim *Dimension) mgl32.Vec3 {
+	a := m.GetAIComp()
+	p := m.GetPhysicsComp()
+	opts := NewPathOptions(p.AABB)
+	f := newPathfinder(dim, opts)
+
+	// Path to the ground below the target, as it may be a player in the air
+	target := PathNodeAt(a.moveTarget.Sub(mgl32.Vec3{p.AABB.X() / 2, 0, p.AABB.Z() / 2}), p.AABB)
+	goal := target
+	for i := 0; i < pathGoalDrop && !f.standable(goal); i++ {
+		goal.Y--
+	}
+	if !f.standable(goal) {
+		goal = target
+	}
+
+	// Only find a new path while on the ground, as the path starts from
+	// where the mob is standing
+	if (a.path == nil || !a.path.Valid() || a.pathGoal != goal) && p.OnGround() {
+		a.path.Release()
+		a.path = FindPath(dim, PathNodeAt(*m.GetPosition(), p.AABB), goal, opts)
+		a.pathGoal = goal
+		a.pathIndex = 1
+	}
+	if a.path == nil {
+		return a.moveTarget
+	}
+
+	// Skip the nodes which have been reached
+	pos := *m.GetPosition()
+	centre := pos.Add(mgl32.Vec3{p.AABB.X() / 2, 0, p.AABB.Z() / 2})
+	for a.pathIndex < len(a.path.Nodes) {
+		node := PathNodeCentre(a.path.Nodes[a.pathIndex], p.AABB)
+		dist := node.Sub(centre)
+		dist[1] = 0
+		if dist.Len() > arriveDistance/2 || FloorFloat32(pos.Y()) < a.path.Nodes[a.pathIndex].Y {
+			break
+		}
+		a.pathIndex++
+	}
+
+	// TODO Find a new path if the mob gets knocked off this one
+	if a.pathIndex >= len(a.path.Nodes) {
+		return a.moveTarget
+	}
+	return PathNodeCentre(a.path.Nodes[a.pathIndex], p.AABB)
+}
+
+// Walks the mob towards its move target along a path, jumping up single blocks
+func steer(m Mob, dim *Dimension) {
+	a := m.GetAIComp()
+	p := m.GetPhysicsComp()
+	pos := *m.GetPosition()
+
+	// Friction, as for players on the ground
+	if p.OnGround() {
+		p.ApplyFriction()
+	}
+
+	if !a.moving {
+		return
+	}
+
+	// Walk from the centre of the mob
+	centre := pos.Add(mgl32.Vec3{p.AABB.X() / 2, 0, p.AABB.Z() / 2})
+	dir := a.moveTarget.Sub(centre)
+	dir[1] = 0
+	if dir.Len() < arriveDistance {
+		a.StopMoving()
+		return
+	}
+
+	dir = waypoint(m, dim).Sub(centre)
+	dir[1] = 0
+	if dir.Len() == 0 {
+		return
+	}
+	dir = dir.Normalize()
+
+	// Face the direction being walked
+	m.GetLookComp().Azimuth = math.Atan2(float64(dir.X()), float64(dir.Z()))
+
+	if !p.OnGround() {
+		return
+	}
+
+	speed := a.Speed * a.moveSpeedMul
+	p.Velocity[0] = dir.X() * speed
+	p.Velocity[2] = dir.Z() * speed
+
+	// Jump if there is a block in the way, with space above it
+	ahead := centre.Add(dir.Mul(p.AABB.X()/2 + 0.3))
+	feet := NewVec3FromFloat(ahead)
+	if dim.GetBlockAt(feet).IsSolid() && !dim.GetBlockAt(feet.Add(Vec3{Y: 1})).IsSolid() {
+		p.Velocity[1] = JUMP_SPEED
+	}
+}
@@ -6,27 +6,33 @@ import (
 )
 
 var Grass = core.AddBlockToRegistry(&core.BlockType{
-	Name:       "mc:grass",
-	RenderType: renderers.BlockBasicOneTex{Tex: "grass"},
+	Name:         "mc:grass",
+	Hardness:     0.9,
+	RenderType:   renderers.BlockBasicOneTex{Tex: "grass"},
+	OnRandomTick: tickGrass,
 })
 
 var Dirt = core.AddBlockToRegistry(&core.BlockType{
 	Name:       "mc:dirt",
+	Hardness:   0.75,
 	RenderType: renderers.BlockBasicOneTex{Tex: "dirt"},
 })
 
 var Stone = core.AddBlockToRegistry(&core.BlockType{
 	Name:       "mc:stone",
+	Hardness:   7.5,
 	RenderType: renderers.BlockBasicOneTex{Tex: "stone"},
 })
 
 var Cobblestone = core.AddBlockToRegistry(&core.BlockType{
 	Name:       "mc:cobblestone",
+	Hardness:   10,
 	RenderType: renderers.BlockBasicOneTex{Tex: "cobblestone"},
 })
 
 var Furnace = core.AddBlockToRegistry(&core.BlockType{
 	Name:           "mc:furnace",
+	Hardness:       17.5,
 	LinkWithEntity: "mc:furnace",
 	RenderType: renderers.BlockBasicSixTex{
 		Top:    "furnace_top",
@@ -37,3 +43,44 @@ var Furnace = core.AddBlockToRegistry(&core.BlockType{
 		Back:   "furnace_side",
 	},
 })
+
+var Chest = core.AddBlockToRegistry(&core.BlockType{
+	Name:           "mc:chest",
+	Hardness:       3.75,
+	LinkWithEntity: "mc:chest",
+	RenderType: renderers.BlockBasicSixTex{
+		Top:    "chest_top",
+		Bottom: "chest_top",
+		Left:   "chest_side",
+		Right:  "chest_side",
+		Front:  "chest_front",
+		Back:   "chest_side",
+	},
+})
+
+var StoneSlab = core.AddBlockToRegistry(&core.BlockType{
+	Name:           "mc:stone_slab",
+	Hardness:       7.5,
+	Transparent:    true,
+	SelectionBoxes: core.SLAB_SHAPE,
+	RenderType:     renderers.BlockBoxesOneTex{Tex: "stone", Boxes: core.SLAB_SHAPE},
+})
+
+var CobblestoneSlab = core.AddBlockToRegistry(&core.BlockType{
+	Name:           "mc:cobblestone_slab",
+	Hardness:       10,
+	Transparent:    true,
+	SelectionBoxes: core.SLAB_SHAPE,
+	RenderType:     renderers.BlockBoxesOneTex{Tex: "cobblestone", Boxes: core.SLAB_SHAPE},
+})
+
+var CobblestoneStairs = core.AddBlockToRegistry(&core.BlockType{
+	Name:           "mc:cobblestone_stairs",
+	Hardness:       10,
+	Transparent:    true,
+	SelectionBoxes: core.STAIRS_SHAPE,
+	RenderType:     renderers.BlockBoxesOneTex{Tex: "cobblestone", Boxes: core.STAIRS_SHAPE},
+})
+
+// TODO Fences, carpets, plants, ice, soul sand, slime and ladders, once there
+// are textures for them
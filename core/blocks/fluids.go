@@ -0,0 +1,43 @@
+package blocks
+
+import (
+	"remakemc/client/renderers"
+	"remakemc/core"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var Water = core.AddFluidToRegistry(&core.FluidType{
+	Name:             "mc:water",
+	FlowDistance:     7,
+	FlowDelay:        5,
+	Infinite:         true,
+	Bucket:           "mc:water_bucket",
+	Drag:             0.8,
+	Gravity:          0.25,
+	SwimAcceleration: 16,
+	Translucent:      true,
+	RenderType:       fluidRenderType("water"),
+})
+
+// TODO Set fire to and hurt bodies in lava, and light up what's around it
+var Lava = core.AddFluidToRegistry(&core.FluidType{
+	Name:             "mc:lava",
+	FlowDistance:     3,
+	FlowDelay:        30,
+	Bucket:           "mc:lava_bucket",
+	Drag:             0.5,
+	Gravity:          0.25,
+	SwimAcceleration: 16,
+	RenderType:       fluidRenderType("lava"),
+})
+
+// Draws fluids as a box up to their surface
+func fluidRenderType(tex string) func(height float32) core.RenderBlockType {
+	return func(height float32) core.RenderBlockType {
+		return renderers.BlockBoxesOneTex{
+			Tex:   tex,
+			Boxes: []core.BlockBox{{Max: mgl32.Vec3{1, height, 1}}},
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package blocks
+
+import "remakemc/core"
+
+// The light grass needs to spread, and to spread onto dirt
+const (
+	GRASS_SPREAD_LIGHT  = 9
+	GRASS_SURVIVE_LIGHT = 4
+)
+
+// The blocks grass tries to spread to each random tick
+const GRASS_SPREAD_ATTEMPTS = 4
+
+// Grass turns back into dirt when covered, and spreads to dirt around it
+// which is lit
+func tickGrass(ctx *core.BlockTickContext, b core.Block) {
+	if !canGrassSurvive(ctx.Dim, b.Position) {
+		ctx.SetBlock(core.Block{Position: b.Position, Type: Dirt})
+		return
+	}
+
+	above := b.Position.Add(core.Vec3{Y: 1})
+	if ctx.Dim.LightAt(above) < GRASS_SPREAD_LIGHT {
+		return
+	}
+
+	// Try blocks up to one away sideways, from three below to one above, as
+	// in Minecraft
+	for i := 0; i < GRASS_SPREAD_ATTEMPTS; i++ {
+		pos := b.Position.Add(core.NewVec3(ctx.Rand.Intn(3)-1, ctx.Rand.Intn(5)-3, ctx.Rand.Intn(3)-1))
+		if ctx.Dim.GetBlockAt(pos).Type == Dirt && canGrassSurvive(ctx.Dim, pos) &&
+			ctx.Dim.LightAt(pos.Add(core.Vec3{Y: 1})) >= GRASS_SURVIVE_LIGHT {
+			ctx.SetBlock(core.Block{Position: pos, Type: b.Type})
+		}
+	}
+}
+
+// Whether grass at the position isn't covered by a solid block, or fluid
+func canGrassSurvive(dim *core.Dimension, pos core.Vec3) bool {
+	above := dim.GetBlockAt(pos.Add(core.Vec3{Y: 1})).Type
+	return above == nil || (above.Transparent && above.Fluid == nil)
+}
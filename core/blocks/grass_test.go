@@ -0,0 +1,100 @@
+package blocks
+
+import (
+	"math/rand"
+	"remakemc/core"
+	"sync"
+	"testing"
+)
+
+// A dimension with a layer of dirt at y=4 from 0 to 4, with grass in the
+// middle, ticked with a seeded random source
+func newGrassTest() (*core.Dimension, *core.BlockTickContext) {
+	d := &core.Dimension{Chunks: make(map[core.Vec3]*core.Chunk), Lock: new(sync.RWMutex)}
+	d.Chunks[core.Vec3{}] = core.NewChunk(core.Vec3{})
+	for x := 0; x <= 4; x++ {
+		for z := 0; z <= 4; z++ {
+			d.SetBlockAt(core.Block{Position: core.NewVec3(x, 4, z), Type: Dirt})
+		}
+	}
+	d.SetBlockAt(core.Block{Position: core.NewVec3(2, 4, 2), Type: Grass})
+
+	return d, &core.BlockTickContext{
+		Dim:      d,
+		Rand:     rand.New(rand.NewSource(1)),
+		SetBlock: d.SetBlockAt,
+	}
+}
+
+func TestGrassSpreads(t *testing.T) {
+	d, ctx := newGrassTest()
+	// Dirt which is covered can't become grass
+	covered := core.NewVec3(1, 4, 1)
+	d.SetBlockAt(core.Block{Position: covered.Add(core.Vec3{Y: 1}), Type: Stone})
+
+	for i := 0; i < 200; i++ {
+		tickGrass(ctx, d.GetBlockAt(core.NewVec3(2, 4, 2)))
+	}
+
+	for x := 1; x <= 3; x++ {
+		for z := 1; z <= 3; z++ {
+			pos := core.NewVec3(x, 4, z)
+			want := Grass
+			if pos == covered {
+				want = Dirt
+			}
+			if got := d.GetBlockAt(pos).Type; got != want {
+				t.Errorf("block at %v is %s, want %s", pos, got.Name, want.Name)
+			}
+		}
+	}
+	// Out of reach of the first grass block, which is the only one ticked
+	if got := d.GetBlockAt(core.NewVec3(0, 4, 0)).Type; got != Dirt {
+		t.Errorf("grass spread two blocks away, to %s", got.Name)
+	}
+}
+
+func TestGrassDoesntSpreadInTheDark(t *testing.T) {
+	d, ctx := newGrassTest()
+	d.Ticks = core.NIGHT_START
+
+	for i := 0; i < 200; i++ {
+		tickGrass(ctx, d.GetBlockAt(core.NewVec3(2, 4, 2)))
+	}
+	for x := 1; x <= 3; x++ {
+		for z := 1; z <= 3; z++ {
+			pos := core.NewVec3(x, 4, z)
+			if got := d.GetBlockAt(pos).Type; pos != core.NewVec3(2, 4, 2) && got != Dirt {
+				t.Errorf("block at %v is %s at night, want dirt", pos, got.Name)
+			}
+		}
+	}
+}
+
+func TestGrassReverts(t *testing.T) {
+	tests := []struct {
+		name  string
+		above *core.BlockType
+		want  *core.BlockType
+	}{
+		{"uncovered", nil, Grass},
+		{"stone", Stone, Dirt},
+		{"slab", StoneSlab, Grass},
+		{"water", Water.Source(), Dirt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ctx := newGrassTest()
+			// Night, so the grass doesn't spread
+			d.Ticks = core.NIGHT_START
+			pos := core.NewVec3(2, 4, 2)
+			d.SetBlockAt(core.Block{Position: pos.Add(core.Vec3{Y: 1}), Type: tt.above})
+
+			tickGrass(ctx, d.GetBlockAt(pos))
+			if got := d.GetBlockAt(pos).Type; got != tt.want {
+				t.Errorf("grass became %s, want %s", got.Name, tt.want.Name)
+			}
+		})
+	}
+}
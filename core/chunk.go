@@ -1,5 +1,11 @@
 package core
 
+import (
+	"errors"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
 // A Chunk is a 16x16x16 group of blocks
 type Chunk struct {
 	EntityBase
@@ -10,6 +16,9 @@ type Chunk struct {
 	MeshLen       int
 	VAO           uint32
 	VertexBuffers []uint32
+	// For Translucent blocks, which are drawn last
+	TranslucentMeshLen int
+	TranslucentVAO     uint32
 
 	// Block data is stored using a palette, to save memory
 	// NB: This data should never be accessed manually, except for
@@ -20,6 +29,41 @@ type Chunk struct {
 	// The number of bits used to represent the index into the palette
 	// 4, 8, or 16
 	PaletteBits int
+
+	// Ticks scheduled for blocks in the chunk. Only the server uses them, so
+	// they aren't sent to clients, but are saved with the chunk.
+	ScheduledTicks []ScheduledTick `msgpack:"-"`
+
+	// Whether blocks have been set through the dimension since the chunk was
+	// generated, so it must be saved
+	Changed bool `msgpack:"-"`
+}
+
+// A chunk as it is saved with the world
+type savedChunk struct {
+	Chunk          *Chunk
+	ScheduledTicks []ScheduledTick
+}
+
+// Encodes the chunk with its scheduled ticks, to be saved with the world
+func SaveChunk(c *Chunk) ([]byte, error) {
+	return msgpack.Marshal(savedChunk{Chunk: c, ScheduledTicks: c.ScheduledTicks})
+}
+
+// Decodes a chunk encoded by SaveChunk. It is marked as changed, so it is
+// saved again.
+func LoadChunk(data []byte) (*Chunk, error) {
+	var s savedChunk
+	if err := msgpack.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if s.Chunk == nil {
+		return nil, errors.New("saved chunk has no blocks")
+	}
+
+	s.Chunk.ScheduledTicks = s.ScheduledTicks
+	s.Chunk.Changed = true
+	return s.Chunk, nil
 }
 
 // Returns an empty chunk
@@ -75,6 +119,7 @@ func (c *Chunk) SetBlockAt(pos Vec3, bl Block) {
 				newData[k*2+1] = v >> 4
 			}
 
+			c.BlockData = newData
 			c.PaletteBits = 8
 		} else {
 			// 8 bits --> 16 bits
@@ -83,6 +128,7 @@ func (c *Chunk) SetBlockAt(pos Vec3, bl Block) {
 				newData[k*2] = v
 			}
 
+			c.BlockData = newData
 			c.PaletteBits = 16
 		}
 	}
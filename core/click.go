@@ -0,0 +1,194 @@
+package core
+
+// These functions implement the semantics of interacting with the slots of a
+// container. They are used by the server to resolve clicks, and by clients
+// to predict the result.
+
+// Picks up the stack in the slot, or puts down the floating stack
+func LeftClickSlot(c Container, index int) {
+	hovered := c.GetSlots()[index]
+
+	if c.GetFloating().IsEmpty() && !hovered.GetStack().IsEmpty() {
+		// Take the stack from the slot
+		s, ok := hovered.TakeStack(false)
+		if ok {
+			c.SetFloating(s)
+		}
+	} else if !c.GetFloating().IsEmpty() {
+		// Place the stack in the slot
+		c.SetFloating(hovered.PutStack(c.GetFloating()))
+	}
+}
+
+// Picks up half the stack in the slot, or puts down one item of the floating stack
+func RightClickSlot(c Container, index int) {
+	hovered := c.GetSlots()[index]
+
+	if c.GetFloating().IsEmpty() && !hovered.GetStack().IsEmpty() {
+		// Take half the stack from the slot
+		s, ok := hovered.TakeStack(true)
+		if ok {
+			c.SetFloating(s)
+		}
+
+	} else if !c.GetFloating().IsEmpty() {
+		if hovered.GetStack().CanStackWith(c.GetFloating()) || hovered.GetStack().IsEmpty() {
+			// Place one item in the slot
+			one := c.GetFloating()
+			one.Count = 1
+			m := hovered.PutStack(one)
+			if m.IsEmpty() {
+				f := c.GetFloating()
+				f.Count--
+				c.SetFloating(f)
+			}
+		} else {
+			// Exchange items
+			c.SetFloating(hovered.PutStack(c.GetFloating()))
+		}
+	}
+}
+
+// Moves the stack in the slot into the slots given by the container's
+// QuickMoveTargets. Existing stacks of the same item are filled first, then
+// empty slots. Slots which don't store items are skipped, and anything that
+// doesn't fit stays in the slot.
+func QuickMoveSlot(c Container, index int) {
+	slots := c.GetSlots()
+	s, ok := slots[index].TakeStack(false)
+	if !ok || s.IsEmpty() {
+		return
+	}
+
+	targets := c.QuickMoveTargets(index)
+
+	// Merge with existing stacks
+	for _, v := range targets {
+		if s.IsEmpty() {
+			break
+		}
+		if v != index && !slots[v].Temp() && !slots[v].GetStack().IsEmpty() && slots[v].GetStack().CanStackWith(s) {
+			s = slots[v].PutStack(s)
+		}
+	}
+
+	// Fill empty slots
+	for _, v := range targets {
+		if s.IsEmpty() {
+			break
+		}
+		if v != index && !slots[v].Temp() && slots[v].GetStack().IsEmpty() {
+			s = slots[v].PutStack(s)
+		}
+	}
+
+	if !s.IsEmpty() {
+		slots[index].SetStack(s)
+	}
+}
+
+// Swaps the stack in the slot with the stack in the nth hotbar slot (0-8)
+func SwapWithHotbar(c Container, index int, n int) {
+	h := c.HotbarIndex(n)
+	if h < 0 || h == index {
+		return
+	}
+
+	slots := c.GetSlots()
+	a := slots[index].GetStack()
+	slots[index].SetStack(slots[h].GetStack())
+	slots[h].SetStack(a)
+}
+
+// Gathers items of the same type as the floating stack from all slots into
+// it, up to the max stack size. Partial stacks are taken from first.
+func CollectToFloating(c Container) {
+	f := c.GetFloating()
+	if f.IsEmpty() {
+		return
+	}
+	mss := ItemRegistry[f.Item].MaxStackSize
+
+	for _, full := range []bool{false, true} {
+		for _, v := range c.GetSlots() {
+			if f.Count >= mss {
+				break
+			}
+
+			s := v.GetStack()
+			if s.IsEmpty() || !s.CanStackWith(f) || (s.Count >= mss) != full {
+				continue
+			}
+
+			taken := s.Count
+			if f.Count+taken > mss {
+				taken = mss - f.Count
+			}
+
+			f.Count += taken
+			s.Count -= taken
+			if s.Count == 0 {
+				s = ItemStack{}
+			}
+			v.SetStack(s)
+		}
+	}
+
+	c.SetFloating(f)
+}
+
+// Distributes the floating stack across the slots, as when dragging the mouse
+// over them. If one is true, one item is placed in each slot, otherwise the
+// stack is split evenly. Slots holding a different item, or which don't store
+// items, are skipped, and any remainder stays floating.
+func PaintSlots(c Container, indices []int, one bool) {
+	f := c.GetFloating()
+	if f.IsEmpty() {
+		return
+	}
+	slots := c.GetSlots()
+
+	// Determine which slots can accept the stack
+	var accepting []int
+	seen := make(map[int]bool)
+	for _, v := range indices {
+		if v < 0 || v >= len(slots) || seen[v] {
+			continue
+		}
+		seen[v] = true
+
+		s := slots[v].GetStack()
+		if !slots[v].Temp() && (s.IsEmpty() || s.CanStackWith(f)) {
+			accepting = append(accepting, v)
+		}
+	}
+	if len(accepting) == 0 {
+		return
+	}
+	// With fewer items than slots, only the first slots get one each, as in
+	// Minecraft
+	if len(accepting) > f.Count {
+		accepting = accepting[:f.Count]
+	}
+
+	per := 1
+	if !one {
+		per = f.Count / len(accepting)
+	}
+
+	for _, v := range accepting {
+		if f.Count < per {
+			break
+		}
+
+		put := f
+		put.Count = per
+		m := slots[v].PutStack(put)
+		f.Count -= per - m.Count
+	}
+
+	if f.Count <= 0 {
+		f = ItemStack{}
+	}
+	c.SetFloating(f)
+}
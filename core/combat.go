@@ -0,0 +1,143 @@
+package core
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// The size of a player, and where their eyes are relative to their position
+var PLAYER_AABB = mgl32.Vec3{0.6, 1.8, 0.6}
+var PLAYER_EYE_OFFSET = mgl32.Vec3{0.3, 1.62, 0.3}
+
+// The same, but while sneaking
+var PLAYER_SNEAKING_AABB = mgl32.Vec3{0.6, 1.5, 0.6}
+var PLAYER_SNEAKING_EYE_OFFSET = mgl32.Vec3{0.3, 1.34, 0.3}
+
+// The furthest an entity can be attacked from, measured from the attacker's eyes
+const ATTACK_REACH = 3
+
+// The furthest a block can be dug or interacted with from, measured from the
+// player's eyes
+const BLOCK_REACH = 16
+
+// The minimum time between attacks, in seconds
+const ATTACK_COOLDOWN = 0.5
+
+// The damage done without a weapon
+const FIST_DAMAGE = 1
+
+// The speed attacked entities are knocked away at, in m/s
+const KNOCKBACK_SPEED = 8
+
+// The damage done by attacking with the stack
+func AttackDamage(s ItemStack) float32 {
+	if s.IsEmpty() || ItemRegistry[s.Item].AttackDamage == 0 {
+		return FIST_DAMAGE
+	}
+	return ItemRegistry[s.Item].AttackDamage
+}
+
+// Knocks the body away in the horizontal direction, and up.
+// Based on https://minecraft.wiki/w/Knockback
+func ApplyKnockback(p *PhysicsComp, dir mgl32.Vec3, speed float32) {
+	dir[1] = 0
+	if dir.Len() == 0 {
+		return
+	}
+	dir = dir.Normalize()
+
+	p.Velocity[0] = p.Velocity[0]/2 + dir.X()*speed
+	p.Velocity[2] = p.Velocity[2]/2 + dir.Z()*speed
+	p.Velocity[1] = float32(math.Min(float64(speed), float64(p.Velocity[1]/2+speed)))
+}
+
+// Finds where the ray enters the box, using the slab method.
+// t is the distance along the ray, which is 0 if it starts inside the box.
+func RayIntersectsAABB(origin, dir, min, max mgl32.Vec3) (t float32, hit bool) {
+	tmin := float32(0)
+	tmax := float32(math.Inf(1))
+
+	for i := 0; i < 3; i++ {
+		if dir[i] == 0 {
+			// Parallel to the slab, so must be within it
+			if origin[i] < min[i] || origin[i] > max[i] {
+				return 0, false
+			}
+			continue
+		}
+
+		t0 := (min[i] - origin[i]) / dir[i]
+		t1 := (max[i] - origin[i]) / dir[i]
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+
+		if t0 > tmin {
+			tmin = t0
+		}
+		if t1 < tmax {
+			tmax = t1
+		}
+		if tmin > tmax {
+			return 0, false
+		}
+	}
+
+	return tmin, true
+}
+
+// The distance from the point to the closest point of the box
+func DistanceToAABB(p, min, max mgl32.Vec3) float32 {
+	var d mgl32.Vec3
+	for i := 0; i < 3; i++ {
+		if p[i] < min[i] {
+			d[i] = min[i] - p[i]
+		} else if p[i] > max[i] {
+			d[i] = p[i] - max[i]
+		}
+	}
+	return d.Len()
+}
+
+// Whether nothing solid blocks the view from the eye to the box, looking at
+// its closest point or its centre
+// You must lock Dim yourself
+func CanSeeAABB(dim *Dimension, eye, min, max mgl32.Vec3) bool {
+	var closest mgl32.Vec3
+	for i := 0; i < 3; i++ {
+		closest[i] = mgl32.Clamp(eye[i], min[i], max[i])
+	}
+	return lineOfSight(dim, eye, closest) || lineOfSight(dim, eye, min.Add(max).Mul(0.5))
+}
+
+// Whether no solid block is between the points
+func lineOfSight(dim *Dimension, from, to mgl32.Vec3) bool {
+	dist := to.Sub(from).Len()
+	if dist == 0 {
+		return true
+	}
+	dir := to.Sub(from).Mul(1 / dist)
+
+	clear := true
+	TraceRay(dir, from, dist, func(v, _ mgl32.Vec3) (stop bool) {
+		b := dim.GetBlockAt(NewVec3FromFloat(v))
+		if !b.IsSolid() {
+			return false
+		}
+		if t, ok := RayIntersectsBoxes(from, dir, b.Position, b.Type.GetCollisionBoxes()); ok && t < dist {
+			clear = false
+			return true
+		}
+		return false
+	})
+	return clear
+}
+
+// Finds the closest entity with a body hit by the ray within reach, ignoring
+// the entity with the excluded ID, such as the one tracing the ray.
+// dir must be normalised.
+func TraceEntities(dir mgl32.Vec3, pos mgl32.Vec3, reach float32, entities *EntityStore, exclude uuid.UUID) (hit Entity, t float32) {
+	return entities.Ray(pos, dir, reach, exclude)
+}
@@ -14,10 +14,33 @@ type Container interface {
 	GetFloating() ItemStack
 	SetFloating(ItemStack)
 
+	// Returns the indices of the slots that the stack in the slot at index
+	// should be moved into when shift clicked, in order of preference.
+	QuickMoveTargets(index int) []int
+
+	// Returns the index of the nth hotbar slot (0-8) within the slots,
+	// or -1 if the container has no hotbar.
+	HotbarIndex(n int) int
+
 	// Render the entire interface. You may use RenderSlots and RenderFloating as helpers.
 	Render()
 }
 
+// A SharedContainer can be viewed by many players at once, such as a chest.
+// Each viewer interacts with the container through their own view of it.
+type SharedContainer interface {
+	Container
+
+	// Returns a container sharing the slots of this container, followed by
+	// the slots of the viewer's inventory. The floating stack is the viewer's.
+	ViewWith(viewer Container) Container
+}
+
+// Entities which hold a container, such as a chest, should implement this
+type ContainerFace interface {
+	GetContainer() Container
+}
+
 func GetStacksFromSlots(slots []Slot) (out []ItemStack) {
 	for _, v := range slots {
 		out = append(out, v.GetStack())
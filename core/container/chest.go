@@ -0,0 +1,143 @@
+package container
+
+import (
+	"remakemc/client/gui"
+	"remakemc/client/renderers"
+	"remakemc/core"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+type Chest struct {
+	EntityID uuid.UUID
+	Slots    []core.Slot
+	Floating core.ItemStack
+
+	// The inventory of the player viewing this chest, if this is a view.
+	// See ViewWith
+	viewer   core.Container
+	slotSize float32
+}
+
+func (c *Chest) Init(withBoxes bool, entityID uuid.UUID) {
+	c.EntityID = entityID
+
+	// Generate slots
+	iwidth := float32(0.8)
+	iheight := iwidth / 170 * 166
+
+	slotAdvance := iwidth / 170 * 18
+	slotContained := iwidth / 170 * 16
+	c.slotSize = slotContained
+
+	// Generate the chest slots, above the player's inventory
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 9; i++ {
+			var start, end mgl32.Vec2
+			if withBoxes {
+				start, end = gui.AnchorAt(
+					mgl32.Vec2{-iwidth/2 + iwidth/170*5 + slotAdvance*float32(i), (-iheight/2 + iwidth/170*138 - slotAdvance*float32(j)) * renderers.GetAspectRatio()},
+					mgl32.Vec2{slotContained, slotContained},
+					gui.Anchor{Horizontal: -1, Vertical: -1},
+				)
+			}
+
+			c.Slots = append(c.Slots, &core.InventorySlot{
+				Start: start, End: end,
+			})
+		}
+	}
+}
+
+// The chest GUI places the player's inventory in the same position as the
+// inventory GUI, so the viewer's slots are reused as is.
+func (c *Chest) ViewWith(viewer core.Container) core.Container {
+	return &Chest{
+		EntityID: c.EntityID,
+		Slots:    c.Slots,
+		viewer:   viewer,
+		slotSize: c.slotSize,
+	}
+}
+
+func (c *Chest) GetEntityID() uuid.UUID {
+	return c.EntityID
+}
+
+func (c *Chest) GetSlots() []core.Slot {
+	if c.viewer == nil {
+		return c.Slots
+	}
+
+	slots := make([]core.Slot, 0, len(c.Slots)+len(c.viewer.GetSlots()))
+	slots = append(slots, c.Slots...)
+	return append(slots, c.viewer.GetSlots()...)
+}
+
+func (c *Chest) GetFloating() core.ItemStack {
+	if c.viewer != nil {
+		return c.viewer.GetFloating()
+	}
+	return c.Floating
+}
+
+func (c *Chest) SetFloating(s core.ItemStack) {
+	if c.viewer != nil {
+		c.viewer.SetFloating(s)
+		return
+	}
+	c.Floating = s
+}
+
+// Shift clicking moves stacks between the chest and the viewer's inventory.
+// Stacks are moved into the main inventory before the hotbar.
+func (c *Chest) QuickMoveTargets(index int) []int {
+	if c.viewer == nil {
+		return nil
+	}
+
+	n := len(c.Slots)
+	if index >= n {
+		return indexRange(0, n)
+	}
+
+	// Find the viewer's hotbar, so that it can be filled last
+	hotbar := make(map[int]bool)
+	for i := 0; i < 9; i++ {
+		hotbar[c.viewer.HotbarIndex(i)] = true
+	}
+
+	var out []int
+	for i := range c.viewer.GetSlots() {
+		if !hotbar[i] {
+			out = append(out, n+i)
+		}
+	}
+	for i := 0; i < 9; i++ {
+		out = append(out, n+c.viewer.HotbarIndex(i))
+	}
+	return out
+}
+
+func (c *Chest) HotbarIndex(n int) int {
+	if c.viewer == nil {
+		return -1
+	}
+	return len(c.Slots) + c.viewer.HotbarIndex(n)
+}
+
+func (c *Chest) Render() {
+	renderers.TintScreen(mgl32.Vec4{0, 0, 0, 0.8})
+
+	iwidth := float32(0.8)
+	iheight := iwidth / 170 * 166
+
+	gui.RenderWithAnchor(gui.Chest, mgl32.Vec2{0, 0}, mgl32.Vec2{iwidth, iheight}, gui.Anchor{Horizontal: 0, Vertical: 0})
+
+	gui.RenderSlots(c.GetSlots())
+	gui.RenderFloating(c.GetFloating(), c.slotSize)
+	if c.GetFloating().IsEmpty() {
+		gui.RenderTooltip(c.GetSlots())
+	}
+}
@@ -0,0 +1,228 @@
+package container
+
+import (
+	"fmt"
+	"remakemc/client/gui"
+	"remakemc/client/renderers"
+	"remakemc/core"
+	"sort"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// The creative palette isn't held by any entity, so it uses the nil ID
+var CreativeID = uuid.Nil
+
+// The number of palette slots shown at once
+const creativeRows = 3
+
+// The creative palette, containing one slot for every registered item.
+// The items matching the search are shown a few rows at a time, which are
+// scrolled through.
+type Creative struct {
+	Slots  []core.Slot
+	Search string
+	// The first row of matching items shown
+	Scroll int
+	// The number of rows of matching items
+	matchingRows int
+
+	viewer    core.Container
+	slotSize  float32
+	withBoxes bool
+}
+
+func (c *Creative) Init(withBoxes bool, entityID uuid.UUID) {
+	c.withBoxes = withBoxes
+
+	// Sort the items, so slot indices are the same on the server and client
+	var names []string
+	for k := range core.ItemRegistry {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, v := range names {
+		c.Slots = append(c.Slots, &core.CreativeSlot{Item: v})
+	}
+
+	c.layoutPalette()
+}
+
+// Gives boxes to the slots matching the search in the rows scrolled to,
+// hiding the rest
+func (c *Creative) layoutPalette() {
+	if !c.withBoxes {
+		return
+	}
+
+	iwidth := float32(0.8)
+	iheight := iwidth / 170 * 166
+
+	slotAdvance := iwidth / 170 * 18
+	slotContained := iwidth / 170 * 16
+	c.slotSize = slotContained
+
+	search := strings.ToLower(c.Search)
+	var matching []*core.CreativeSlot
+	for _, v := range c.Slots {
+		s := v.(*core.CreativeSlot)
+		s.Start, s.End = mgl32.Vec2{}, mgl32.Vec2{}
+
+		name := strings.ToLower(core.ItemStack{Item: s.Item, Count: 1}.GetDisplayName())
+		if strings.Contains(name, search) || strings.Contains(s.Item, search) {
+			matching = append(matching, s)
+		}
+	}
+
+	// Don't scroll past the last row
+	c.matchingRows = (len(matching) + 8) / 9
+	if c.Scroll > c.matchingRows-creativeRows {
+		c.Scroll = c.matchingRows - creativeRows
+	}
+	if c.Scroll < 0 {
+		c.Scroll = 0
+	}
+
+	start := c.Scroll * 9
+	for shown, s := range matching[start:] {
+		if shown >= creativeRows*9 {
+			break
+		}
+
+		// Use the same positions as the chest slots
+		i, j := shown%9, shown/9
+		s.Start, s.End = gui.AnchorAt(
+			mgl32.Vec2{-iwidth/2 + iwidth/170*5 + slotAdvance*float32(i), (-iheight/2 + iwidth/170*138 - slotAdvance*float32(j)) * renderers.GetAspectRatio()},
+			mgl32.Vec2{slotContained, slotContained},
+			gui.Anchor{Horizontal: -1, Vertical: -1},
+		)
+	}
+}
+
+// Scrolls the matching items by the number of rows, down for positive rows
+func (c *Creative) ScrollRows(rows int) {
+	c.Scroll += rows
+	c.layoutPalette()
+}
+
+func (c *Creative) InputChar(r rune) {
+	if r < 0x20 || r >= 0x7f {
+		// Only ascii can be rendered
+		return
+	}
+
+	c.Search += string(r)
+	c.Scroll = 0
+	c.layoutPalette()
+}
+
+func (c *Creative) InputBackspace() {
+	if len(c.Search) > 0 {
+		c.Search = c.Search[:len(c.Search)-1]
+		c.Scroll = 0
+		c.layoutPalette()
+	}
+}
+
+func (c *Creative) ViewWith(viewer core.Container) core.Container {
+	return &Creative{
+		Slots:        c.Slots,
+		Search:       c.Search,
+		Scroll:       c.Scroll,
+		matchingRows: c.matchingRows,
+		viewer:       viewer,
+		slotSize:     c.slotSize,
+		withBoxes:    c.withBoxes,
+	}
+}
+
+func (c *Creative) GetEntityID() uuid.UUID {
+	return CreativeID
+}
+
+func (c *Creative) GetSlots() []core.Slot {
+	if c.viewer == nil {
+		return c.Slots
+	}
+
+	slots := make([]core.Slot, 0, len(c.Slots)+len(c.viewer.GetSlots()))
+	slots = append(slots, c.Slots...)
+	return append(slots, c.viewer.GetSlots()...)
+}
+
+func (c *Creative) GetFloating() core.ItemStack {
+	if c.viewer == nil {
+		return core.ItemStack{}
+	}
+	return c.viewer.GetFloating()
+}
+
+func (c *Creative) SetFloating(s core.ItemStack) {
+	if c.viewer != nil {
+		c.viewer.SetFloating(s)
+	}
+}
+
+// Shift clicking the palette moves a full stack into the viewer's inventory
+func (c *Creative) QuickMoveTargets(index int) []int {
+	if c.viewer == nil || index >= len(c.Slots) {
+		return nil
+	}
+
+	n := len(c.Slots)
+	var out []int
+	for i := 0; i < 9; i++ {
+		out = append(out, n+c.viewer.HotbarIndex(i))
+	}
+	for i := range c.viewer.GetSlots() {
+		if i >= 9 {
+			out = append(out, n+i)
+		}
+	}
+	return out
+}
+
+func (c *Creative) HotbarIndex(n int) int {
+	if c.viewer == nil {
+		return -1
+	}
+	return len(c.Slots) + c.viewer.HotbarIndex(n)
+}
+
+func (c *Creative) Render() {
+	renderers.TintScreen(mgl32.Vec4{0, 0, 0, 0.8})
+
+	iwidth := float32(0.8)
+	iheight := iwidth / 170 * 166
+
+	gui.RenderWithAnchor(gui.Chest, mgl32.Vec2{0, 0}, mgl32.Vec2{iwidth, iheight}, gui.Anchor{Horizontal: 0, Vertical: 0})
+	gui.RenderText(
+		mgl32.Vec2{-iwidth/2 + iwidth/170*5, (iheight/2 - iwidth/170*2) * renderers.GetAspectRatio()},
+		"Search: "+c.Search+"_",
+		gui.Anchor{Horizontal: -1, Vertical: 1},
+	)
+	if c.matchingRows > creativeRows {
+		gui.RenderText(
+			mgl32.Vec2{iwidth/2 - iwidth/170*5, (iheight/2 - iwidth/170*2) * renderers.GetAspectRatio()},
+			fmt.Sprintf("Rows %d-%d of %d", c.Scroll+1, c.Scroll+creativeRows, c.matchingRows),
+			gui.Anchor{Horizontal: 1, Vertical: 1},
+		)
+	}
+
+	// Only render the slots which are shown
+	var slots []core.Slot
+	for _, v := range c.GetSlots() {
+		if start, end := v.GetBox(); start != end {
+			slots = append(slots, v)
+		}
+	}
+
+	gui.RenderSlots(slots)
+	gui.RenderFloating(c.GetFloating(), c.slotSize)
+	if c.GetFloating().IsEmpty() {
+		gui.RenderTooltip(slots)
+	}
+}
@@ -79,6 +79,18 @@ func (c *Inventory) SetFloating(s core.ItemStack) {
 	c.Floating = s
 }
 
+// Shift clicking moves stacks between the hotbar and the rest of the inventory
+func (c *Inventory) QuickMoveTargets(index int) []int {
+	if index < 9 {
+		return indexRange(9, len(c.Slots))
+	}
+	return indexRange(0, 9)
+}
+
+func (c *Inventory) HotbarIndex(n int) int {
+	return n
+}
+
 func (c *Inventory) Render() {
 	renderers.TintScreen(mgl32.Vec4{0, 0, 0, 0.8})
 
@@ -89,4 +101,15 @@ func (c *Inventory) Render() {
 
 	gui.RenderSlots(c.GetSlots())
 	gui.RenderFloating(c.GetFloating(), c.slotSize)
+	if c.GetFloating().IsEmpty() {
+		gui.RenderTooltip(c.GetSlots())
+	}
+}
+
+// Returns the indices from start up to, but not including, end
+func indexRange(start, end int) (out []int) {
+	for i := start; i < end; i++ {
+		out = append(out, i)
+	}
+	return
 }
@@ -3,37 +3,70 @@ package entities
 import (
 	"remakemc/client/renderers"
 	"remakemc/core"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
 )
 
 type RemotePlayer struct {
 	core.EntityBase
 	core.PositionComp
+	core.PhysicsComp // only used for the AABB, as the position is lerped
 	core.LerpComp
 	core.LookComp
+	core.MetadataComp
 }
 
 func (r *RemotePlayer) GetTypeName() string {
 	return "mc:remote_player"
 }
 
+// Shrinks the player while they are sneaking
+func (r *RemotePlayer) SetSneaking(b bool) {
+	if b {
+		r.AABB = core.PLAYER_SNEAKING_AABB
+	} else {
+		r.AABB = core.PLAYER_AABB
+	}
+}
+
 func (r *RemotePlayer) RenderInit() {
 	r.GetRenderComp().Init()
 }
 
 func (r *RemotePlayer) GetRenderComp() core.RenderEntityType {
-	return &renderers.TestEntityRenderer{
-		Vertices: []float32{
-			0, 0, 0,
-			0.6, 0, 0.6,
-			0.6, 1.8, 0.6,
-
-			0.6, 1.8, 0.6,
-			0.6, 0, 0.6,
-			0, 0, 0,
-		},
-
-		Shader: "mc:test_entity",
-	}
+	return remotePlayerRenderer
+}
+
+// Shared by all remote players, so it is only initialised once
+var remotePlayerRenderer = &renderers.TestEntityRenderer{
+	Vertices: []float32{
+		0, 0, 0,
+		0.6, 0, 0.6,
+		0.6, 1.8, 0.6,
+
+		0.6, 1.8, 0.6,
+		0.6, 0, 0.6,
+		0, 0, 0,
+	},
+
+	Shader: "mc:test_entity",
+	Height: 1.8,
+
+	HeldItem: &renderers.TestEntityRenderer{
+		Vertices: boxVertices(mgl32.Vec3{0.2, 0.2, 0.2}),
+		Shader:   "mc:test_entity",
+	},
+	HandOffset: mgl32.Vec3{0.6, 0.8, 0.2},
 }
 
-var _ = core.AddEntityToRegistry(new(RemotePlayer))
+// Players are saved by the server separately
+var _ = core.AddEntityToRegistry(&core.EntityType{
+	Name: "mc:remote_player",
+	New: func(id uuid.UUID) core.Entity {
+		return &RemotePlayer{
+			EntityBase:  core.EntityBase{ID: id},
+			PhysicsComp: core.PhysicsComp{AABB: core.PLAYER_AABB},
+		}
+	},
+})
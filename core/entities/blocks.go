@@ -1,7 +1,74 @@
 package entities
 
+import (
+	"fmt"
+	"remakemc/core"
+	"remakemc/core/container"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
 // var Furnace = core.AddEntityToRegistry(&core.EntityType{
 // 	Name:       "mc:furnace",
 // 	IsBlock:    true,
 // 	RenderType: nil,
 // })
+
+// The block entity linked with a chest block, holding its contents
+type Chest struct {
+	core.EntityBase
+	core.PositionComp
+
+	Container *container.Chest
+}
+
+func (c *Chest) GetTypeName() string {
+	return "mc:chest"
+}
+
+func (c *Chest) GetContainer() core.Container {
+	return c.Container
+}
+
+// What is saved about a chest, which is found again from its position
+type chestSave struct {
+	Position mgl32.Vec3
+	Stacks   []core.ItemStack
+}
+
+// Clients are sent the contents when they open the chest, so it has no spawn
+// data
+var _ = core.AddEntityToRegistry(&core.EntityType{
+	Name: "mc:chest",
+	New: func(id uuid.UUID) core.Entity {
+		e := &Chest{
+			EntityBase: core.EntityBase{ID: id},
+			Container:  new(container.Chest),
+		}
+		e.Container.Init(false, id)
+		return e
+	},
+	Save: func(e core.Entity) ([]byte, error) {
+		c := e.(*Chest)
+		return msgpack.Marshal(chestSave{
+			Position: c.Position,
+			Stacks:   core.GetStacksFromSlots(c.Container.Slots),
+		})
+	},
+	Load: func(e core.Entity, data []byte) error {
+		var s chestSave
+		if err := msgpack.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		c := e.(*Chest)
+		if len(s.Stacks) != len(c.Container.Slots) {
+			return fmt.Errorf("chest has %d slots, not %d", len(c.Container.Slots), len(s.Stacks))
+		}
+
+		c.Position = s.Position
+		core.SetSlotsFromStacks(s.Stacks, c.Container.Slots)
+		return nil
+	},
+})
@@ -0,0 +1,248 @@
+package entities
+
+import (
+	"remakemc/client/renderers"
+	"remakemc/core"
+	"remakemc/core/blocks"
+	"remakemc/core/items"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// A passive mob, which follows players holding apples and runs when hurt
+type Pig struct {
+	core.EntityBase
+	core.PositionComp
+	core.PhysicsComp
+	core.LookComp
+	core.LerpComp
+	core.HealthComp
+	core.AIComp
+	core.MetadataComp
+}
+
+func NewPig(id uuid.UUID, pos mgl32.Vec3) *Pig {
+	return &Pig{
+		EntityBase:   core.EntityBase{ID: id},
+		PositionComp: core.PositionComp{Position: pos},
+		PhysicsComp:  core.PhysicsComp{AABB: mgl32.Vec3{0.9, 0.9, 0.9}, StepHeight: core.DEFAULT_STEP_HEIGHT, Floats: true},
+		HealthComp:   core.NewHealthComp(10),
+		AIComp: core.AIComp{
+			Speed: 2.5,
+			Goals: []core.Goal{
+				&core.FleeGoal{SpeedMul: 2, Duration: 60},
+				&core.FollowPlayerGoal{Items: []string{items.Apple.Name}, Range: 10, Distance: 2},
+				&core.WanderGoal{Chance: 0.01, Range: 8},
+			},
+		},
+	}
+}
+
+func (p *Pig) GetTypeName() string {
+	return "mc:pig"
+}
+
+func (p *Pig) RenderInit() {
+	p.GetRenderComp().Init()
+}
+
+func (p *Pig) GetRenderComp() core.RenderEntityType {
+	return pigRenderer
+}
+
+var pigRenderer = &renderers.TestEntityRenderer{
+	Vertices: boxVertices(mgl32.Vec3{0.9, 0.9, 0.9}),
+	Shader:   "mc:test_entity",
+}
+
+var _ = core.AddEntityToRegistry(&core.EntityType{
+	Name: "mc:pig",
+	New: func(id uuid.UUID) core.Entity {
+		return NewPig(id, mgl32.Vec3{})
+	},
+	EncodeSpawn: encodeMobSpawn,
+	DecodeSpawn: decodeMobSpawn,
+	Save:        saveMob,
+	Load:        loadMob,
+})
+
+var _ = core.AddSpawnRule(&core.SpawnRule{
+	Entity:    "mc:pig",
+	Category:  core.SpawnPassive,
+	Placement: core.SpawnSurface,
+	MinLight:  9,
+	MaxLight:  core.MAX_LIGHT,
+	Biomes:    []core.Biome{core.BiomePlains},
+	Ground:    []string{blocks.Grass.Name},
+	MinGroup:  2,
+	MaxGroup:  4,
+	Weight:    10,
+})
+
+// A hostile mob, which chases and attacks players in survival
+type Zombie struct {
+	core.EntityBase
+	core.PositionComp
+	core.PhysicsComp
+	core.LookComp
+	core.LerpComp
+	core.HealthComp
+	core.AIComp
+	core.MetadataComp
+}
+
+func NewZombie(id uuid.UUID, pos mgl32.Vec3) *Zombie {
+	return &Zombie{
+		EntityBase:   core.EntityBase{ID: id},
+		PositionComp: core.PositionComp{Position: pos},
+		PhysicsComp:  core.PhysicsComp{AABB: mgl32.Vec3{0.6, 1.95, 0.6}, StepHeight: core.DEFAULT_STEP_HEIGHT, Floats: true},
+		HealthComp:   core.NewHealthComp(20),
+		AIComp: core.AIComp{
+			Speed: 2.3,
+			Goals: []core.Goal{
+				&core.AttackGoal{Range: 16, Reach: 1.2, Damage: 3, Cooldown: 20},
+				&core.WanderGoal{Chance: 0.01, Range: 8},
+			},
+		},
+	}
+}
+
+func (z *Zombie) GetTypeName() string {
+	return "mc:zombie"
+}
+
+func (z *Zombie) RenderInit() {
+	z.GetRenderComp().Init()
+}
+
+func (z *Zombie) GetRenderComp() core.RenderEntityType {
+	return zombieRenderer
+}
+
+var zombieRenderer = &renderers.TestEntityRenderer{
+	Vertices: boxVertices(mgl32.Vec3{0.6, 1.95, 0.6}),
+	Shader:   "mc:test_entity",
+}
+
+var _ = core.AddEntityToRegistry(&core.EntityType{
+	Name: "mc:zombie",
+	New: func(id uuid.UUID) core.Entity {
+		return NewZombie(id, mgl32.Vec3{})
+	},
+	EncodeSpawn: encodeMobSpawn,
+	DecodeSpawn: decodeMobSpawn,
+	Save:        saveMob,
+	Load:        loadMob,
+})
+
+// Zombies spawn in the dark, in caves and on the surface at night
+var _ = core.AddSpawnRule(&core.SpawnRule{
+	Entity:    "mc:zombie",
+	Category:  core.SpawnHostile,
+	Placement: core.SpawnUnderground,
+	MinLight:  0,
+	MaxLight:  7,
+	MinGroup:  1,
+	MaxGroup:  4,
+	Weight:    100,
+})
+
+var _ = core.AddSpawnRule(&core.SpawnRule{
+	Entity:    "mc:zombie",
+	Category:  core.SpawnHostile,
+	Placement: core.SpawnSurface,
+	MinLight:  0,
+	MaxLight:  7,
+	MinGroup:  1,
+	MaxGroup:  4,
+	Weight:    100,
+})
+
+// The components shared by pigs and zombies
+type mob interface {
+	core.PositionFace
+	core.PhysicsFace
+	core.LookFace
+	core.HealthFace
+	core.MetadataFace
+}
+
+// What clients need to show a mob, other than its position. Its health and
+// AI stay on the server.
+type mobSpawn struct {
+	Metadata core.EntityMetadata
+}
+
+func encodeMobSpawn(e core.Entity) ([]byte, error) {
+	return msgpack.Marshal(mobSpawn{
+		Metadata: e.(mob).GetMetadataComp().Metadata,
+	})
+}
+
+func decodeMobSpawn(e core.Entity, data []byte) error {
+	var s mobSpawn
+	if err := msgpack.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	e.(mob).GetMetadataComp().Metadata = s.Metadata
+	return nil
+}
+
+// What is saved about a mob. Its AI starts again from its goals when loaded.
+type mobSave struct {
+	Position mgl32.Vec3
+	Velocity mgl32.Vec3
+	Look     core.LookComp
+	Health   core.HealthComp
+}
+
+func saveMob(e core.Entity) ([]byte, error) {
+	m := e.(mob)
+	return msgpack.Marshal(mobSave{
+		Position: *m.GetPosition(),
+		Velocity: m.GetPhysicsComp().Velocity,
+		Look:     *m.GetLookComp(),
+		Health:   *m.GetHealthComp(),
+	})
+}
+
+func loadMob(e core.Entity, data []byte) error {
+	var s mobSave
+	if err := msgpack.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	m := e.(mob)
+	*m.GetPosition() = s.Position
+	m.GetPhysicsComp().Velocity = s.Velocity
+	*m.GetLookComp() = s.Look
+	*m.GetHealthComp() = s.Health
+	return nil
+}
+
+// The triangles of a box of the size, with a corner at the origin
+func boxVertices(size mgl32.Vec3) []float32 {
+	x, y, z := size.X(), size.Y(), size.Z()
+	return []float32{
+		// Bottom
+		0, 0, 0, x, 0, 0, x, 0, z,
+		x, 0, z, 0, 0, z, 0, 0, 0,
+		// Top
+		0, y, 0, 0, y, z, x, y, z,
+		x, y, z, x, y, 0, 0, y, 0,
+		// Back
+		0, 0, 0, 0, y, 0, x, y, 0,
+		x, y, 0, x, 0, 0, 0, 0, 0,
+		// Front
+		0, 0, z, x, 0, z, x, y, z,
+		x, y, z, 0, y, z, 0, 0, z,
+		// Left
+		0, 0, 0, 0, 0, z, 0, y, z,
+		0, y, z, 0, y, 0, 0, 0, 0,
+		// Right
+		x, 0, 0, x, y, 0, x, y, z,
+		x, y, z, x, 0, z, x, 0, 0,
+	}
+}
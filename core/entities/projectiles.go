@@ -0,0 +1,176 @@
+package entities
+
+import (
+	"remakemc/client/renderers"
+	"remakemc/core"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Shot from a bow, and sticks into the blocks it hits
+type Arrow struct {
+	core.EntityBase
+	core.PositionComp
+	core.PhysicsComp
+	core.LookComp
+	core.LerpComp
+	core.ProjectileComp
+}
+
+func NewArrow(id uuid.UUID, pos mgl32.Vec3, owner uuid.UUID) *Arrow {
+	return &Arrow{
+		EntityBase:   core.EntityBase{ID: id},
+		PositionComp: core.PositionComp{Position: pos},
+		PhysicsComp: core.PhysicsComp{
+			AABB:      mgl32.Vec3{0.5, 0.5, 0.5},
+			NoGravity: true,
+			NoClip:    true,
+		},
+		// Based on https://minecraft.wiki/w/Arrow
+		ProjectileComp: core.ProjectileComp{
+			Owner:         owner,
+			Damage:        6,
+			Gravity:       20,
+			Drag:          0.99,
+			Sticks:        true,
+			StuckLifetime: 1200,
+		},
+	}
+}
+
+func (a *Arrow) GetTypeName() string {
+	return "mc:arrow"
+}
+
+func (a *Arrow) RenderInit() {
+	a.GetRenderComp().Init()
+}
+
+func (a *Arrow) GetRenderComp() core.RenderEntityType {
+	return arrowRenderer
+}
+
+var arrowRenderer = &renderers.TestEntityRenderer{
+	Vertices: boxVertices(mgl32.Vec3{0.1, 0.1, 0.5}),
+	Shader:   "mc:test_entity",
+}
+
+var _ = core.AddEntityToRegistry(&core.EntityType{
+	Name: "mc:arrow",
+	New: func(id uuid.UUID) core.Entity {
+		return NewArrow(id, mgl32.Vec3{}, uuid.Nil)
+	},
+	Save: saveProjectile,
+	Load: loadProjectile,
+})
+
+// Thrown by players, and breaks on whatever it hits
+type Snowball struct {
+	core.EntityBase
+	core.PositionComp
+	core.PhysicsComp
+	core.LookComp
+	core.LerpComp
+	core.ProjectileComp
+}
+
+func NewSnowball(id uuid.UUID, pos mgl32.Vec3, owner uuid.UUID) *Snowball {
+	return &Snowball{
+		EntityBase:   core.EntityBase{ID: id},
+		PositionComp: core.PositionComp{Position: pos},
+		PhysicsComp: core.PhysicsComp{
+			AABB:      mgl32.Vec3{0.25, 0.25, 0.25},
+			NoGravity: true,
+			NoClip:    true,
+		},
+		// Snowballs do no damage, but still knock back
+		ProjectileComp: core.ProjectileComp{
+			Owner:   owner,
+			Gravity: 12,
+			Drag:    0.99,
+		},
+	}
+}
+
+func (s *Snowball) GetTypeName() string {
+	return "mc:snowball"
+}
+
+func (s *Snowball) RenderInit() {
+	s.GetRenderComp().Init()
+}
+
+func (s *Snowball) GetRenderComp() core.RenderEntityType {
+	return snowballRenderer
+}
+
+var snowballRenderer = &renderers.TestEntityRenderer{
+	Vertices: boxVertices(mgl32.Vec3{0.25, 0.25, 0.25}),
+	Shader:   "mc:test_entity",
+}
+
+var _ = core.AddEntityToRegistry(&core.EntityType{
+	Name: "mc:snowball",
+	New: func(id uuid.UUID) core.Entity {
+		return NewSnowball(id, mgl32.Vec3{}, uuid.Nil)
+	},
+	Save: saveProjectile,
+	Load: loadProjectile,
+})
+
+// The components shared by arrows and snowballs. Clients only need their
+// position, so they have no spawn data.
+type projectile interface {
+	core.PositionFace
+	core.PhysicsFace
+	core.LookFace
+	core.ProjectileFace
+}
+
+// What is saved about a projectile. How it flies and the damage it does come
+// from its type.
+type projectileSave struct {
+	Position mgl32.Vec3
+	Velocity mgl32.Vec3
+	Look     core.LookComp
+
+	Owner     uuid.UUID
+	Stuck     bool
+	StuckIn   core.Vec3
+	StuckTime int
+}
+
+func saveProjectile(e core.Entity) ([]byte, error) {
+	p := e.(projectile)
+	c := p.GetProjectileComp()
+	return msgpack.Marshal(projectileSave{
+		Position:  *p.GetPosition(),
+		Velocity:  p.GetPhysicsComp().Velocity,
+		Look:      *p.GetLookComp(),
+		Owner:     c.Owner,
+		Stuck:     c.Stuck,
+		StuckIn:   c.StuckIn,
+		StuckTime: c.StuckTime,
+	})
+}
+
+func loadProjectile(e core.Entity, data []byte) error {
+	var s projectileSave
+	if err := msgpack.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	p := e.(projectile)
+	*p.GetPosition() = s.Position
+	p.GetPhysicsComp().Velocity = s.Velocity
+	*p.GetLookComp() = s.Look
+
+	c := p.GetProjectileComp()
+	c.Owner = s.Owner
+	c.Stuck = s.Stuck
+	c.StuckIn = s.StuckIn
+	c.StuckTime = s.StuckTime
+	return nil
+}
@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"remakemc/core"
+	"remakemc/core/items"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// Saves the entity and loads it into a new entity of the same type
+func saveAndLoad(t *testing.T, e core.Entity) core.Entity {
+	t.Helper()
+	typ := core.EntityRegistry[e.GetTypeName()]
+
+	data, err := typ.Save(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := typ.New(e.GetID())
+	if err := typ.Load(loaded, data); err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+func TestSaveMob(t *testing.T) {
+	z := NewZombie(uuid.New(), mgl32.Vec3{1, 2, 3})
+	z.Velocity = mgl32.Vec3{0, -4, 0}
+	z.Yaw = 1.5
+	z.Damage(5, 100)
+
+	loaded := saveAndLoad(t, z).(*Zombie)
+	if loaded.Position != z.Position || loaded.Velocity != z.Velocity {
+		t.Errorf("loaded at %v moving %v, want %v moving %v", loaded.Position, loaded.Velocity, z.Position, z.Velocity)
+	}
+	if loaded.LookComp != z.LookComp {
+		t.Errorf("loaded look %v, want %v", loaded.LookComp, z.LookComp)
+	}
+	if loaded.HealthComp != z.HealthComp {
+		t.Errorf("loaded health %v, want %v", loaded.HealthComp, z.HealthComp)
+	}
+	// The AI isn't saved, but comes from the type
+	if len(loaded.Goals) != len(z.Goals) {
+		t.Errorf("loaded with %d goals, want %d", len(loaded.Goals), len(z.Goals))
+	}
+}
+
+func TestSaveProjectile(t *testing.T) {
+	owner := uuid.New()
+	a := NewArrow(uuid.New(), mgl32.Vec3{1, 2, 3}, owner)
+	a.Stuck = true
+	a.StuckIn = core.Vec3{X: 1, Y: 1, Z: 3}
+	a.StuckTime = 40
+
+	loaded := saveAndLoad(t, a).(*Arrow)
+	if loaded.ProjectileComp != a.ProjectileComp {
+		t.Errorf("loaded %+v, want %+v", loaded.ProjectileComp, a.ProjectileComp)
+	}
+	if loaded.Position != a.Position {
+		t.Errorf("loaded at %v, want %v", loaded.Position, a.Position)
+	}
+}
+
+func TestSaveChest(t *testing.T) {
+	typ := core.EntityRegistry["mc:chest"]
+	c := typ.New(uuid.New()).(*Chest)
+	c.Position = mgl32.Vec3{4, 5, 6}
+	stack := core.ItemStack{Item: items.Apple.Name, Count: 3}
+	core.MetaDisplayName.Set(&stack, "Lunch")
+	c.Container.Slots[10].SetStack(stack)
+
+	loaded := saveAndLoad(t, c).(*Chest)
+	if loaded.Position != c.Position {
+		t.Errorf("loaded at %v, want %v", loaded.Position, c.Position)
+	}
+	for i, v := range loaded.Container.Slots {
+		got, want := v.GetStack(), c.Container.Slots[i].GetStack()
+		if got.Count != want.Count || !got.CanStackWith(want) {
+			t.Errorf("slot %d holds %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestMobSpawnData(t *testing.T) {
+	p := NewPig(uuid.New(), mgl32.Vec3{})
+	p.Metadata.HeldItem = items.Apple.Name
+	typ := core.EntityRegistry["mc:pig"]
+
+	data, err := typ.EncodeSpawn(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := typ.New(p.ID).(*Pig)
+	if err := typ.DecodeSpawn(remote, data); err != nil {
+		t.Fatal(err)
+	}
+	if remote.Metadata != p.Metadata {
+		t.Errorf("decoded metadata %v, want %v", remote.Metadata, p.Metadata)
+	}
+}
@@ -21,13 +21,25 @@ func (b *EntityBase) GetID() uuid.UUID {
 	return b.ID
 }
 
-func GetEntitiesSatisfying[V any](entities []Entity) (out []V) {
-	for _, v := range entities {
-		if e, ok := v.(V); ok {
-			out = append(out, e)
-		}
-	}
-	return
+type EntityType struct {
+	// The registered name of this entity type, the same as GetTypeName.
+	// It should be in the format of namespace:entity
+	Name string
+
+	// Creates an entity of the type with the ID, with its components set to
+	// their defaults. The position should be set by the caller.
+	New func(id uuid.UUID) Entity
+
+	// Encodes the data clients need to create their copy of the entity,
+	// other than what is in proto.EntityPosition, and decodes it into a new
+	// entity. Nil if nothing else is needed.
+	EncodeSpawn func(e Entity) ([]byte, error)
+	DecodeSpawn func(e Entity, data []byte) error
+
+	// Encodes the entity to be saved with the world, and decodes it into a
+	// new entity when the world is loaded. Nil if the entity isn't saved.
+	Save func(e Entity) ([]byte, error)
+	Load func(e Entity, data []byte) error
 }
 
 type PositionFace interface {
@@ -61,10 +73,6 @@ type RenderFace interface {
 	GetRenderComp() RenderEntityType
 }
 
-// type EntityEquipment struct {
-// 	HeldItemType string
-// }
-
 // type EntityType struct {
 // 	Name       string
 // 	RenderType RenderEntityType
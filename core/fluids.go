@@ -0,0 +1,288 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// How high a fluid source fills its block, so fluid surfaces are below the
+// blocks around them
+const FLUID_SOURCE_HEIGHT = 8.0 / 9
+
+// Fluids, such as water and lava, are made of a block type for each level of
+// the fluid. Sources have level 0, and the fluid gets shallower as it flows
+// away, until it reaches FlowDistance. Fluids falling down from above are a
+// separate level, which flows like a source when it lands.
+// The level is part of the block type, as chunks store blocks by type name.
+type FluidType struct {
+	// The name of the source block, such as mc:water. Flowing levels are
+	// called mc:water_1 and so on, and falling fluid mc:water_falling.
+	Name string
+	// How far the fluid flows from a source on flat ground
+	FlowDistance int
+	// The ticks between each block the fluid flows
+	FlowDelay int
+	// Whether two sources make a new source between them
+	Infinite bool
+
+	// The item for a bucket of the fluid
+	Bucket string
+
+	// How much of the body's velocity is left after each tick in the fluid
+	Drag float32
+	// How much of gravity bodies feel in the fluid, so they sink slowly
+	Gravity float32
+	// The upwards acceleration of bodies swimming or floating, in m/s^2
+	SwimAcceleration float32
+
+	// Returns how the fluid is drawn with its surface at the height
+	// TODO Flowing textures and sloped surfaces
+	RenderType func(height float32) RenderBlockType
+	// Whether the fluid can be seen through, such as water
+	Translucent bool
+
+	// By level, with falling last
+	blocks []*BlockType
+}
+
+// Registers the fluid's blocks. The fluid itself is registered by the name of
+// its source.
+func AddFluidToRegistry(f *FluidType) *FluidType {
+	for level := 0; level <= f.FlowDistance+1; level++ {
+		name := fmt.Sprintf("%s_%d", f.Name, level)
+		if level == 0 {
+			name = f.Name
+		} else if level == f.FlowDistance+1 {
+			name = f.Name + "_falling"
+		}
+
+		b := &BlockType{
+			Name:        name,
+			Transparent: true,
+			Translucent: f.Translucent,
+			NoCollision: true,
+			Fluid:       f,
+			FluidLevel:  level,
+
+			OnTick:          tickFluid,
+			UpdateTickDelay: f.FlowDelay,
+		}
+		b.SelectionBoxes = []BlockBox{{Max: mgl32.Vec3{1, b.FluidHeight(), 1}}}
+		if f.RenderType != nil {
+			b.RenderType = f.RenderType(b.FluidHeight())
+		}
+
+		f.blocks = append(f.blocks, AddBlockToRegistry(b))
+	}
+
+	FluidRegistry[f.Name] = f
+	return f
+}
+
+// The block type of the fluid's source
+func (f *FluidType) Source() *BlockType {
+	return f.blocks[0]
+}
+
+// The block type of fluid falling from above
+func (f *FluidType) Falling() *BlockType {
+	return f.blocks[len(f.blocks)-1]
+}
+
+// The block type of flowing fluid at the level, from 1 to FlowDistance
+func (f *FluidType) Flowing(level int) *BlockType {
+	return f.blocks[level]
+}
+
+// Returns the fluid which fills the bucket item, or nil
+func FluidFromBucket(item string) *FluidType {
+	for _, v := range FluidRegistry {
+		if v.Bucket == item {
+			return v
+		}
+	}
+	return nil
+}
+
+// Whether the block is a source of its fluid, and so can be picked up
+func (t *BlockType) IsFluidSource() bool {
+	return t != nil && t.Fluid != nil && t.FluidLevel == 0
+}
+
+func (t *BlockType) isFluidFalling() bool {
+	return t.Fluid != nil && t.FluidLevel == t.Fluid.FlowDistance+1
+}
+
+// How high the fluid fills its block
+func (t *BlockType) FluidHeight() float32 {
+	switch {
+	case t.Fluid == nil:
+		return 0
+	case t.isFluidFalling():
+		return 1
+	default:
+		// Each level is lower, with the last just above the bottom
+		f := float32(t.Fluid.FlowDistance+1-t.FluidLevel) / float32(t.Fluid.FlowDistance+1)
+		return f * FLUID_SOURCE_HEIGHT
+	}
+}
+
+// The level which flows out of the block sideways, which falling fluid
+// flows out as if it were a source
+func (t *BlockType) flowLevel() int {
+	if t.isFluidFalling() {
+		return 0
+	}
+	return t.FluidLevel
+}
+
+// Whether fluid can flow into the block, replacing it with the fluid at the
+// level. Fluids only replace empty blocks, and shallower flowing fluid. Level
+// 0 is falling fluid, which replaces any flowing fluid.
+func canFlowInto(b Block, f *FluidType, level int) bool {
+	if b.Type == nil {
+		return true
+	}
+	// TODO Mixing fluids, such as lava and water making stone
+	if b.Type.Fluid != f || b.Type.FluidLevel == 0 {
+		return false
+	}
+	return !b.Type.isFluidFalling() && (b.Type.FluidLevel > level || level == 0)
+}
+
+// Flows the fluid, FlowDelay ticks after it or anything next to it changes
+func tickFluid(ctx *BlockTickContext, b Block) {
+	for _, v := range FlowFluid(ctx.Dim, b.Position) {
+		ctx.SetBlock(v)
+	}
+}
+
+// Works out how the fluid at the position flows. Returns the blocks which
+// change, including the block itself.
+// You must lock Dim yourself
+func FlowFluid(dim *Dimension, pos Vec3) []Block {
+	b := dim.GetBlockAt(pos)
+	if b.Type == nil || b.Type.Fluid == nil {
+		return nil
+	}
+	f := b.Type.Fluid
+
+	var changes []Block
+	t := b.Type
+
+	// Flowing fluid is fed by what's around it, and dries up without it
+	if t.FluidLevel != 0 {
+		t = fedFluid(dim, pos, f)
+		if t != b.Type {
+			changes = append(changes, Block{Position: pos, Type: t})
+		}
+		if t == nil {
+			return changes
+		}
+	}
+
+	// Fall rather than spreading, if there's room
+	below := dim.GetBlockAt(pos.Add(Vec3{Y: -1}))
+	if canFlowInto(below, f, 0) {
+		if dim.GetChunkContaining(below.Position) != nil {
+			changes = append(changes, Block{Position: below.Position, Type: f.Falling()})
+		}
+		return changes
+	}
+	// Fluid falling into more of itself doesn't spread either, though sources
+	// spread over sources
+	if below.Type != nil && below.Type.Fluid == f && (t.FluidLevel != 0 || below.Type.FluidLevel != 0) {
+		return changes
+	}
+
+	// Spread sideways
+	// TODO Flow towards the nearest drop, as in Minecraft
+	level := t.flowLevel() + 1
+	if level > f.FlowDistance {
+		return changes
+	}
+	for _, v := range horizontalFaces {
+		n := dim.GetBlockAt(pos.Add(FaceDirection[v]))
+		if dim.GetChunkContaining(n.Position) != nil && canFlowInto(n, f, level) {
+			changes = append(changes, Block{Position: n.Position, Type: f.Flowing(level)})
+		}
+	}
+
+	return changes
+}
+
+var horizontalFaces = []BlockFace{FaceLeft, FaceRight, FaceFront, FaceBack}
+
+// Returns what flowing fluid at the position should be, from the fluid
+// flowing into it from above and from its sides. Returns nil if nothing
+// flows into it.
+func fedFluid(dim *Dimension, pos Vec3, f *FluidType) *BlockType {
+	if above := dim.GetBlockAt(pos.Add(Vec3{Y: 1})); above.Type != nil && above.Type.Fluid == f {
+		return f.Falling()
+	}
+
+	level := f.FlowDistance + 1
+	sources := 0
+	for _, v := range horizontalFaces {
+		n := dim.GetBlockAt(pos.Add(FaceDirection[v]))
+		if n.Type == nil || n.Type.Fluid != f {
+			continue
+		}
+		if n.Type.FluidLevel == 0 {
+			sources++
+		}
+		if l := n.Type.flowLevel() + 1; l < level {
+			level = l
+		}
+	}
+
+	// Two sources make another, if it has something to sit on
+	if f.Infinite && sources >= 2 {
+		below := dim.GetBlockAt(pos.Add(Vec3{Y: -1}))
+		if below.IsSolid() || below.Type.IsFluidSource() {
+			return f.Source()
+		}
+	}
+
+	if level > f.FlowDistance {
+		return nil
+	}
+	return f.Flowing(level)
+}
+
+// Returns the fluid the body at the position is in, or nil. Bodies are in
+// fluid if it is above their feet.
+func fluidAt(dim *Dimension, pos, aabb mgl32.Vec3) *FluidType {
+	feet := pos.Add(mgl32.Vec3{aabb.X() / 2, 0, aabb.Z() / 2})
+	b := dim.GetBlockAt(NewVec3FromFloat(feet))
+	if b.Type == nil || b.Type.Fluid == nil {
+		return nil
+	}
+
+	if feet.Y()-float32(b.Position.Y) < b.Type.FluidHeight() {
+		return b.Type.Fluid
+	}
+	return nil
+}
+
+// The fluid the body is in, or nil
+func (p *PhysicsComp) InFluid() *FluidType {
+	return p.fluid
+}
+
+// Accelerates the body for a tick in the fluid, instead of applyGravity.
+// Fluids hold bodies up and slow them down, and bodies which float swim up.
+func applyFluid(e *PhysicsComp, f *FluidType) {
+	if !e.NoGravity {
+		e.Velocity = e.Velocity.Add(GRAVITY.Mul(f.Gravity / TICK_RATE))
+	}
+	if e.Floats {
+		e.Velocity[1] += f.SwimAcceleration / TICK_RATE
+	}
+	// TODO Push bodies along with flowing fluid
+	e.Velocity = e.Velocity.Mul(f.Drag)
+
+	// Landing in fluid doesn't hurt
+	e.landingSpeed = 0
+}
@@ -0,0 +1,63 @@
+package core
+
+import "strings"
+
+type GameMode int
+
+const (
+	// Blocks take time to dig, and placing blocks consumes them
+	Survival GameMode = iota
+
+	// Blocks break instantly, items are unlimited, and players can fly
+	Creative
+
+	// Players fly through blocks, and can't interact with the world
+	Spectator
+)
+
+var gameModeNames = map[GameMode]string{
+	Survival:  "survival",
+	Creative:  "creative",
+	Spectator: "spectator",
+}
+
+func (g GameMode) String() string {
+	return gameModeNames[g]
+}
+
+// Parses the name of a game mode, as returned by String
+func ParseGameMode(name string) (GameMode, bool) {
+	for k, v := range gameModeNames {
+		if v == strings.ToLower(name) {
+			return k, true
+		}
+	}
+	return Survival, false
+}
+
+func (g GameMode) CanFly() bool {
+	return g == Creative || g == Spectator
+}
+
+func (g GameMode) InstantBreak() bool {
+	return g == Creative
+}
+
+func (g GameMode) ConsumesItems() bool {
+	return g == Survival
+}
+
+// Whether the player takes damage from falling and attacks
+func (g GameMode) TakesDamage() bool {
+	return g == Survival
+}
+
+// Whether the player can dig, place, and use containers
+func (g GameMode) CanInteract() bool {
+	return g != Spectator
+}
+
+// Whether the player passes through blocks
+func (g GameMode) NoClip() bool {
+	return g == Spectator
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Walks to random nearby positions now and then
+type WanderGoal struct {
+	Chance float32 // the chance of starting each tick
+	Range  float32
+
+	ticks int
+}
+
+func (g *WanderGoal) CanStart(m Mob, ctx *AIContext) bool {
+	return ctx.Rand.Float32() < g.Chance
+}
+
+func (g *WanderGoal) ShouldContinue(m Mob, ctx *AIContext) bool {
+	// Give up if the position can't be reached
+	return m.GetAIComp().Moving() && g.ticks < 200
+}
+
+func (g *WanderGoal) Start(m Mob, ctx *AIContext) {
+	g.ticks = 0
+
+	angle := ctx.Rand.Float64() * 2 * math.Pi
+	dist := ctx.Rand.Float32() * g.Range
+	target := m.GetPosition().Add(mgl32.Vec3{
+		float32(math.Sin(angle)) * dist,
+		0,
+		float32(math.Cos(angle)) * dist,
+	})
+	m.GetAIComp().MoveTo(target, 1)
+}
+
+func (g *WanderGoal) Tick(m Mob, ctx *AIContext) {
+	g.ticks++
+}
+
+func (g *WanderGoal) Stop(m Mob) {
+	m.GetAIComp().StopMoving()
+}
+
+// Follows the nearest player holding one of the items, or any player if
+// there are no items
+type FollowPlayerGoal struct {
+	Items    []string
+	Range    float32
+	Distance float32 // how close to follow
+
+	target AITarget
+}
+
+func (g *FollowPlayerGoal) find(m Mob, ctx *AIContext) (AITarget, bool) {
+	return ctx.NearestPlayer(*m.GetPosition(), g.Range, func(t AITarget) bool {
+		if len(g.Items) == 0 {
+			return true
+		}
+		for _, v := range g.Items {
+			if t.HeldItem == v {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (g *FollowPlayerGoal) CanStart(m Mob, ctx *AIContext) bool {
+	t, ok := g.find(m, ctx)
+	return ok && t.Position.Sub(*m.GetPosition()).Len() > g.Distance
+}
+
+func (g *FollowPlayerGoal) ShouldContinue(m Mob, ctx *AIContext) bool {
+	t, ok := g.find(m, ctx)
+	return ok && t.ID == g.target.ID
+}
+
+func (g *FollowPlayerGoal) Start(m Mob, ctx *AIContext) {
+	g.target, _ = g.find(m, ctx)
+}
+
+func (g *FollowPlayerGoal) Tick(m Mob, ctx *AIContext) {
+	g.target, _ = g.find(m, ctx)
+
+	if g.target.Position.Sub(*m.GetPosition()).Len() > g.Distance {
+		m.GetAIComp().MoveTo(g.target.Position, 1)
+	} else {
+		m.GetAIComp().StopMoving()
+	}
+}
+
+func (g *FollowPlayerGoal) Stop(m Mob) {
+	m.GetAIComp().StopMoving()
+}
+
+// Runs away from the nearest player after being hurt
+type FleeGoal struct {
+	SpeedMul float32
+	Duration int // in ticks
+
+	// The tick of the dimension the mob last started fleeing on
+	started int
+	ticks   int
+}
+
+// Flees if hurt since last fleeing, within the duration. Mobs hurt while doing
+// something more important flee afterwards.
+func (g *FleeGoal) CanStart(m Mob, ctx *AIContext) bool {
+	hurt := m.GetHealthComp().LastHurt
+	return hurt > g.started && ctx.Dim.Ticks-hurt < g.Duration
+}
+
+func (g *FleeGoal) ShouldContinue(m Mob, ctx *AIContext) bool {
+	return g.ticks < g.Duration
+}
+
+func (g *FleeGoal) Start(m Mob, ctx *AIContext) {
+	g.started = ctx.Dim.Ticks
+	g.ticks = 0
+	g.flee(m, ctx)
+}
+
+func (g *FleeGoal) Tick(m Mob, ctx *AIContext) {
+	g.ticks++
+
+	if !m.GetAIComp().Moving() {
+		g.flee(m, ctx)
+	}
+}
+
+// Picks somewhere away from the nearest player, or anywhere if there are none
+func (g *FleeGoal) flee(m Mob, ctx *AIContext) {
+	pos := *m.GetPosition()
+
+	var dir mgl32.Vec3
+	if t, ok := ctx.NearestPlayer(pos, 16, nil); ok {
+		dir = pos.Sub(t.Position)
+	}
+	dir[1] = 0
+	if dir.Len() == 0 {
+		angle := ctx.Rand.Float64() * 2 * math.Pi
+		dir = mgl32.Vec3{float32(math.Sin(angle)), 0, float32(math.Cos(angle))}
+	}
+
+	m.GetAIComp().MoveTo(pos.Add(dir.Normalize().Mul(8)), g.SpeedMul)
+}
+
+func (g *FleeGoal) Stop(m Mob) {
+	m.GetAIComp().StopMoving()
+}
+
+// Chases the nearest attackable player, and hits them when in reach
+type AttackGoal struct {
+	Range    float32
+	Reach    float32 // from the centre of the mob to the player's body
+	Damage   float32
+	Cooldown int // ticks between attacks
+
+	target   AITarget
+	cooldown int
+}
+
+func (g *AttackGoal) find(m Mob, ctx *AIContext) (AITarget, bool) {
+	return ctx.NearestPlayer(*m.GetPosition(), g.Range, func(t AITarget) bool {
+		return t.Attackable
+	})
+}
+
+func (g *AttackGoal) CanStart(m Mob, ctx *AIContext) bool {
+	_, ok := g.find(m, ctx)
+	return ok
+}
+
+func (g *AttackGoal) ShouldContinue(m Mob, ctx *AIContext) bool {
+	_, ok := g.find(m, ctx)
+	return ok
+}
+
+func (g *AttackGoal) Start(m Mob, ctx *AIContext) {
+	g.target, _ = g.find(m, ctx)
+}
+
+func (g *AttackGoal) Tick(m Mob, ctx *AIContext) {
+	g.target, _ = g.find(m, ctx)
+	m.GetAIComp().MoveTo(g.target.Position, 1)
+
+	if g.cooldown > 0 {
+		g.cooldown--
+		return
+	}
+
+	// Hit the player if they are in reach
+	centre := m.GetPosition().Add(m.GetPhysicsComp().AABB.Mul(0.5))
+	if DistanceToAABB(centre, g.target.Position, g.target.Position.Add(PLAYER_AABB)) <= g.Reach {
+		ctx.Attack(m, g.target.ID, g.Damage)
+		g.cooldown = g.Cooldown
+	}
+}
+
+func (g *AttackGoal) Stop(m Mob) {
+	m.GetAIComp().StopMoving()
+}
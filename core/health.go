@@ -0,0 +1,102 @@
+package core
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The health players spawn with
+const PLAYER_MAX_HEALTH = 20
+
+// Falling slower than this when landing does no damage, in m/s.
+// Equivalent to falling 3 blocks.
+const SAFE_FALL_SPEED = 13.86
+
+// Entities below this height take void damage
+const VOID_LEVEL = -64
+
+// The damage taken every VOID_DAMAGE_INTERVAL ticks in the void
+const VOID_DAMAGE = 4
+const VOID_DAMAGE_INTERVAL = 10
+
+type HealthFace interface {
+	GetHealthComp() *HealthComp
+}
+
+// Health is in half hearts, so a player has 20
+type HealthComp struct {
+	Health    float32
+	MaxHealth float32
+
+	// The tick of the dimension the entity was last damaged on, or 0
+	LastHurt int
+}
+
+func NewHealthComp(max float32) HealthComp {
+	return HealthComp{Health: max, MaxHealth: max}
+}
+
+func (h *HealthComp) GetHealthComp() *HealthComp {
+	return h
+}
+
+// Reduces the health on the tick of the dimension, returning whether the
+// damage killed the entity
+func (h *HealthComp) Damage(amount float32, tick int) (died bool) {
+	if h.Dead() || amount <= 0 {
+		return false
+	}
+
+	h.LastHurt = tick
+	h.Health -= amount
+	if h.Health <= 0 {
+		h.Health = 0
+		return true
+	}
+	return false
+}
+
+// Increases the health, up to the max
+func (h *HealthComp) Heal(amount float32) {
+	if h.Dead() {
+		return
+	}
+
+	h.Health += amount
+	if h.Health > h.MaxHealth {
+		h.Health = h.MaxHealth
+	}
+}
+
+func (h *HealthComp) Dead() bool {
+	return h.Health <= 0
+}
+
+// Restores full health, such as when respawning
+func (h *HealthComp) Reset() {
+	h.Health = h.MaxHealth
+}
+
+// The damage taken when landing at the speed, in m/s.
+// Ignoring drag, a fall of h blocks lands at v = sqrt(2gh), so h = v^2/2g.
+func FallDamage(landingSpeed float32) float32 {
+	if landingSpeed <= SAFE_FALL_SPEED {
+		return 0
+	}
+
+	return FallDistanceDamage(landingSpeed * landingSpeed / (2 * -GRAVITY.Y()))
+}
+
+// The damage taken when landing after falling the distance, in blocks.
+// Minecraft does one damage per block fallen beyond the third.
+func FallDistanceDamage(distance float32) float32 {
+	if distance <= 3 {
+		return 0
+	}
+	return float32(math.Ceil(float64(distance - 3)))
+}
+
+func InVoid(pos mgl32.Vec3) bool {
+	return pos.Y() < VOID_LEVEL
+}
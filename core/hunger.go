@@ -0,0 +1,106 @@
+package core
+
+// The food level players spawn with, and can't eat beyond
+const MAX_FOOD = 20
+
+// Players can't sprint at or below this food level
+const SPRINT_FOOD_LEVEL = 6
+
+// The exhaustion caused by actions. Every 4 exhaustion removes one saturation,
+// or one food once there is no saturation.
+const (
+	EXHAUSTION_JUMP        = 0.05
+	EXHAUSTION_SPRINT_JUMP = 0.2
+	EXHAUSTION_SPRINTING   = 0.028 // per tick, about 0.1 per block
+	EXHAUSTION_REGENERATE  = 6
+)
+
+// The number of ticks between regenerating or starving
+const HUNGER_TICK_INTERVAL = 80
+
+// Properties of items which can be eaten
+type FoodType struct {
+	Hunger             int     // the food restored
+	SaturationModifier float32 // the saturation restored, per food restored
+}
+
+type HungerFace interface {
+	GetHungerComp() *HungerComp
+}
+
+// Food is in half drumsticks, so a player has 20.
+// Saturation is used up before food, and can't be higher than the food level.
+type HungerComp struct {
+	Food       int
+	Saturation float32
+	Exhaustion float32
+
+	tickTimer int
+}
+
+func NewHungerComp() HungerComp {
+	return HungerComp{Food: MAX_FOOD, Saturation: 5}
+}
+
+func (h *HungerComp) GetHungerComp() *HungerComp {
+	return h
+}
+
+func (h *HungerComp) Exhaust(amount float32) {
+	h.Exhaustion += amount
+	for h.Exhaustion >= 4 {
+		h.Exhaustion -= 4
+		if h.Saturation > 0 {
+			h.Saturation--
+			if h.Saturation < 0 {
+				h.Saturation = 0
+			}
+		} else if h.Food > 0 {
+			h.Food--
+		}
+	}
+}
+
+func (h *HungerComp) CanEat() bool {
+	return h.Food < MAX_FOOD
+}
+
+func (h *HungerComp) CanSprint() bool {
+	return h.Food > SPRINT_FOOD_LEVEL
+}
+
+func (h *HungerComp) Eat(f *FoodType) {
+	h.Food += f.Hunger
+	if h.Food > MAX_FOOD {
+		h.Food = MAX_FOOD
+	}
+
+	h.Saturation += float32(f.Hunger) * f.SaturationModifier * 2
+	if h.Saturation > float32(h.Food) {
+		h.Saturation = float32(h.Food)
+	}
+}
+
+// Advances the hunger by a tick. Returns the health to regenerate when fed,
+// and the damage to take from starving when empty.
+func (h *HungerComp) Tick(needsHealing bool) (heal float32, starve float32) {
+	switch {
+	case h.Food >= 18 && needsHealing:
+		h.tickTimer++
+		if h.tickTimer >= HUNGER_TICK_INTERVAL {
+			h.tickTimer = 0
+			h.Exhaust(EXHAUSTION_REGENERATE)
+			return 1, 0
+		}
+	case h.Food == 0:
+		h.tickTimer++
+		if h.tickTimer >= HUNGER_TICK_INTERVAL {
+			h.tickTimer = 0
+			return 0, 1
+		}
+	default:
+		h.tickTimer = 0
+	}
+
+	return 0, 0
+}
@@ -1,23 +1,183 @@
 package core
 
-import "github.com/go-gl/mathgl/mgl32"
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/vmihailenco/msgpack/v5"
+)
 
 type ItemType struct {
 	Name         string
+	DisplayName  string
 	MaxStackSize int
 	RenderType   RenderItemType
+
+	// The number of uses before the item breaks, or 0 if it never breaks
+	MaxDamage int
+
+	// The damage done when attacking with the item, or 0 to use FIST_DAMAGE
+	AttackDamage float32
+
+	// The ticks the use button must be held to use the item, or 0 if it can't be used
+	UseDuration int
+	// Set if the item can be eaten, when used
+	Food *FoodType
+	// The type of the projectile entity shot when the item is used, if any
+	Shoots string
+	// The speed projectiles are shot at, in m/s
+	ShootSpeed float32
+	// The item used up by shooting, or empty if the item itself is used up
+	Ammo string
+	// Set if the item picks up fluid sources, such as empty buckets, becoming
+	// the fluid's Bucket
+	PicksUpFluid bool
+	// The fluid placed when the item is used on a block, such as by water
+	// buckets, and the item it leaves behind
+	PlacesFluid string
+	EmptiesTo   string
 	// TODO Interaction func
 }
 
 type ItemStack struct {
 	Item  string
 	Count int
+	Meta  ItemMeta `msgpack:",omitempty"`
 }
 
 func (i ItemStack) IsEmpty() bool {
 	return i.Item == "" || i.Count <= 0
 }
 
+// Whether the two stacks can be merged into one. Stacks with different
+// metadata can't be merged.
+func (i ItemStack) CanStackWith(o ItemStack) bool {
+	return i.Item == o.Item && i.Meta.Equal(o.Meta)
+}
+
+// Returns the name to show for the stack. This is the custom name, if one is set
+func (i ItemStack) GetDisplayName() string {
+	if name, ok := MetaDisplayName.Get(i); ok {
+		return name
+	}
+
+	t := ItemRegistry[i.Item]
+	if t == nil || t.DisplayName == "" {
+		return i.Item
+	}
+	return t.DisplayName
+}
+
+// Damages the item by n uses, returning true if it has broken.
+// Items that can't be damaged are unaffected.
+func (i *ItemStack) AddDamage(n int) (broken bool) {
+	t := ItemRegistry[i.Item]
+	if t == nil || t.MaxDamage == 0 {
+		return false
+	}
+
+	d, _ := MetaDamage.Get(*i)
+	d += n
+	if d >= t.MaxDamage {
+		*i = ItemStack{}
+		return true
+	}
+
+	MetaDamage.Set(i, d)
+	return false
+}
+
+// ItemMeta holds extra properties of a stack, such as a custom name.
+// The values are stored msgpack encoded, and accessed using a MetaKey.
+//
+// An ItemMeta must not be modified once it is part of a stack, as copies of
+// the stack share it. MetaKey.Set copies it instead.
+type ItemMeta map[string]msgpack.RawMessage
+
+func (m ItemMeta) Equal(o ItemMeta) bool {
+	if len(m) != len(o) {
+		return false
+	}
+
+	for k, v := range m {
+		w, ok := o[k]
+		if !ok {
+			return false
+		}
+		if bytes.Equal(v, w) {
+			continue
+		}
+
+		// Maps may be encoded in any order, so compare the decoded values
+		var a, b interface{}
+		if msgpack.Unmarshal(v, &a) != nil || msgpack.Unmarshal(w, &b) != nil || !reflect.DeepEqual(a, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// A MetaKey is the name of a property in an ItemMeta, with the type of its value.
+// It should be in the format of namespace:property
+type MetaKey[T any] string
+
+var (
+	MetaDamage      = MetaKey[int]("mc:damage")
+	MetaDisplayName = MetaKey[string]("mc:display_name")
+	MetaLore        = MetaKey[[]string]("mc:lore")
+
+	// Enchantment-like modifiers, from the name of the modifier to its level
+	MetaModifiers = MetaKey[map[string]int]("mc:modifiers")
+)
+
+// Gets the value of the property from the stack
+func (k MetaKey[T]) Get(s ItemStack) (v T, ok bool) {
+	raw, ok := s.Meta[string(k)]
+	if !ok {
+		return v, false
+	}
+
+	if err := msgpack.Unmarshal(raw, &v); err != nil {
+		return v, false
+	}
+	return v, true
+}
+
+// Sets the value of the property on the stack
+func (k MetaKey[T]) Set(s *ItemStack, v T) {
+	b, err := msgpack.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	m := s.Meta.clone()
+	m[string(k)] = b
+	s.Meta = m
+}
+
+// Removes the property from the stack
+func (k MetaKey[T]) Remove(s *ItemStack) {
+	if _, ok := s.Meta[string(k)]; !ok {
+		return
+	}
+
+	m := s.Meta.clone()
+	delete(m, string(k))
+	if len(m) == 0 {
+		m = nil
+	}
+	s.Meta = m
+}
+
+func (m ItemMeta) clone() ItemMeta {
+	out := make(ItemMeta, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
 type RenderItemType interface {
 	Init()
 	RenderItem(i *ItemType, boxStart mgl32.Vec2, boxEnd mgl32.Vec2)
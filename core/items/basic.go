@@ -7,6 +7,7 @@ import (
 
 var Cobblestone = core.AddItemToRegistry(&core.ItemType{
 	Name:         "mc:cobblestone",
+	DisplayName:  "Cobblestone",
 	MaxStackSize: 64,
 	RenderType: &renderers.ItemFromBlock{
 		Block: "mc:cobblestone",
@@ -15,6 +16,7 @@ var Cobblestone = core.AddItemToRegistry(&core.ItemType{
 
 var Grass = core.AddItemToRegistry(&core.ItemType{
 	Name:         "mc:grass",
+	DisplayName:  "Grass Block",
 	MaxStackSize: 64,
 	RenderType: &renderers.ItemFromBlock{
 		Block: "mc:grass",
@@ -23,6 +25,7 @@ var Grass = core.AddItemToRegistry(&core.ItemType{
 
 var Dirt = core.AddItemToRegistry(&core.ItemType{
 	Name:         "mc:dirt",
+	DisplayName:  "Dirt",
 	MaxStackSize: 64,
 	RenderType: &renderers.ItemFromBlock{
 		Block: "mc:dirt",
@@ -31,6 +34,7 @@ var Dirt = core.AddItemToRegistry(&core.ItemType{
 
 var Stone = core.AddItemToRegistry(&core.ItemType{
 	Name:         "mc:stone",
+	DisplayName:  "Stone",
 	MaxStackSize: 64,
 	RenderType: &renderers.ItemFromBlock{
 		Block: "mc:stone",
@@ -39,8 +43,127 @@ var Stone = core.AddItemToRegistry(&core.ItemType{
 
 var Furnace = core.AddItemToRegistry(&core.ItemType{
 	Name:         "mc:furnace",
+	DisplayName:  "Furnace",
 	MaxStackSize: 64,
 	RenderType: &renderers.ItemFromBlock{
 		Block: "mc:furnace",
 	},
 })
+
+var Chest = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:chest",
+	DisplayName:  "Chest",
+	MaxStackSize: 64,
+	RenderType: &renderers.ItemFromBlock{
+		Block: "mc:chest",
+	},
+})
+
+var StoneSlab = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:stone_slab",
+	DisplayName:  "Stone Slab",
+	MaxStackSize: 64,
+	RenderType: &renderers.ItemFromBlock{
+		Block: "mc:stone_slab",
+	},
+})
+
+var CobblestoneSlab = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:cobblestone_slab",
+	DisplayName:  "Cobblestone Slab",
+	MaxStackSize: 64,
+	RenderType: &renderers.ItemFromBlock{
+		Block: "mc:cobblestone_slab",
+	},
+})
+
+var CobblestoneStairs = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:cobblestone_stairs",
+	DisplayName:  "Cobblestone Stairs",
+	MaxStackSize: 64,
+	RenderType: &renderers.ItemFromBlock{
+		Block: "mc:cobblestone_stairs",
+	},
+})
+
+var Bucket = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:bucket",
+	DisplayName:  "Bucket",
+	MaxStackSize: 16,
+	PicksUpFluid: true,
+	RenderType:   &renderers.ItemFlat{Tex: "bucket"},
+})
+
+var WaterBucket = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:water_bucket",
+	DisplayName:  "Water Bucket",
+	MaxStackSize: 1,
+	PlacesFluid:  "mc:water",
+	EmptiesTo:    "mc:bucket",
+	RenderType:   &renderers.ItemFlat{Tex: "water_bucket"},
+})
+
+var LavaBucket = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:lava_bucket",
+	DisplayName:  "Lava Bucket",
+	MaxStackSize: 1,
+	PlacesFluid:  "mc:lava",
+	EmptiesTo:    "mc:bucket",
+	RenderType:   &renderers.ItemFlat{Tex: "lava_bucket"},
+})
+
+var Apple = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:apple",
+	DisplayName:  "Apple",
+	MaxStackSize: 64,
+	UseDuration:  32,
+	Food:         &core.FoodType{Hunger: 4, SaturationModifier: 0.3},
+	RenderType:   &renderers.ItemFlat{Tex: "apple"},
+})
+
+var Bread = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:bread",
+	DisplayName:  "Bread",
+	MaxStackSize: 64,
+	UseDuration:  32,
+	Food:         &core.FoodType{Hunger: 5, SaturationModifier: 0.6},
+	RenderType:   &renderers.ItemFlat{Tex: "bread"},
+})
+
+var StoneSword = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:stone_sword",
+	DisplayName:  "Stone Sword",
+	MaxStackSize: 1,
+	MaxDamage:    131,
+	AttackDamage: 5,
+	RenderType:   &renderers.ItemFlat{Tex: "stone_sword"},
+})
+
+var Bow = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:bow",
+	DisplayName:  "Bow",
+	MaxStackSize: 1,
+	MaxDamage:    384,
+	UseDuration:  20,
+	Shoots:       "mc:arrow",
+	ShootSpeed:   60,
+	Ammo:         "mc:arrow",
+	RenderType:   &renderers.ItemFlat{Tex: "bow"},
+})
+
+var Arrow = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:arrow",
+	DisplayName:  "Arrow",
+	MaxStackSize: 64,
+	RenderType:   &renderers.ItemFlat{Tex: "arrow"},
+})
+
+var Snowball = core.AddItemToRegistry(&core.ItemType{
+	Name:         "mc:snowball",
+	DisplayName:  "Snowball",
+	MaxStackSize: 16,
+	UseDuration:  4,
+	Shoots:       "mc:snowball",
+	ShootSpeed:   30,
+	RenderType:   &renderers.ItemFlat{Tex: "snowball"},
+})
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// How long positions are kept for, which must be longer than MAX_REWIND
+const POSITION_HISTORY_LENGTH = time.Second
+
+// The furthest back targets are rewound, so players with a lot of latency
+// can't hit what everyone else has long since seen move away
+const MAX_REWIND = 300 * time.Millisecond
+
+// Where an entity was, and how big it was, at a time on the server
+type positionRecord struct {
+	Time     time.Duration
+	Position mgl32.Vec3
+	AABB     mgl32.Vec3
+}
+
+// Remembers where entities were recently, so hits can be checked against
+// where an attacker saw their target, rather than where it is now, which is
+// called lag compensation. The zero value is an empty history.
+// You must lock Dim yourself
+type PositionHistory struct {
+	records map[uuid.UUID][]positionRecord
+}
+
+// Records where the entity is at the server time. Times must not go
+// backwards, and a record at the same time as the last replaces it.
+func (h *PositionHistory) Record(id uuid.UUID, t time.Duration, pos, aabb mgl32.Vec3) {
+	if h.records == nil {
+		h.records = make(map[uuid.UUID][]positionRecord)
+	}
+
+	r := h.records[id]
+	if len(r) > 0 && r[len(r)-1].Time == t {
+		r = r[:len(r)-1]
+	}
+	r = append(r, positionRecord{Time: t, Position: pos, AABB: aabb})
+
+	// Drop records too old to be rewound to, keeping one older than that to
+	// interpolate from
+	drop := 0
+	for drop+1 < len(r) && r[drop+1].Time <= t-POSITION_HISTORY_LENGTH {
+		drop++
+	}
+	h.records[id] = r[drop:]
+}
+
+// Forgets the entity, such as when it is removed
+func (h *PositionHistory) Forget(id uuid.UUID) {
+	delete(h.records, id)
+}
+
+// Returns where the entity was at the server time, interpolating between the
+// records either side of it. Times before the first record give the first, and
+// after the last give the last. Returns false if nothing is recorded.
+func (h *PositionHistory) Rewind(id uuid.UUID, t time.Duration) (pos, aabb mgl32.Vec3, ok bool) {
+	r := h.records[id]
+	if len(r) == 0 {
+		return mgl32.Vec3{}, mgl32.Vec3{}, false
+	}
+
+	if t <= r[0].Time {
+		return r[0].Position, r[0].AABB, true
+	}
+
+	for i := 1; i < len(r); i++ {
+		if t > r[i].Time {
+			continue
+		}
+
+		a, b := r[i-1], r[i]
+		if b.Time <= a.Time {
+			return b.Position, b.AABB, true
+		}
+		s := float32(t-a.Time) / float32(b.Time-a.Time)
+		return a.Position.Add(b.Position.Sub(a.Position).Mul(s)), b.AABB, true
+	}
+
+	last := r[len(r)-1]
+	return last.Position, last.AABB, true
+}
+
+// The server time a player saw when they acted, from their round trip time.
+// Their action took half the round trip to arrive, and they saw the server
+// half a round trip late, INTERPOLATION_DELAY in the past. Rewinds no further
+// than MAX_REWIND.
+func ViewTime(now, rtt time.Duration) time.Duration {
+	rewind := rtt + INTERPOLATION_DELAY
+	if rewind > MAX_REWIND {
+		rewind = MAX_REWIND
+	}
+	if rewind < 0 {
+		rewind = 0
+	}
+	return now - rewind
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+func TestViewTime(t *testing.T) {
+	now := 10 * time.Second
+	tests := []struct {
+		rtt, want time.Duration
+	}{
+		{0, now - INTERPOLATION_DELAY},
+		{50 * ms, now - 50*ms - INTERPOLATION_DELAY},
+		// Laggy players can't rewind further than MAX_REWIND
+		{MAX_REWIND, now - MAX_REWIND},
+		{5 * time.Second, now - MAX_REWIND},
+	}
+	for _, v := range tests {
+		if got := ViewTime(now, v.rtt); got != v.want {
+			t.Errorf("view time with RTT %v should be %v, got %v", v.rtt, v.want, got)
+		}
+	}
+}
+
+func TestRewindBetweenRecords(t *testing.T) {
+	var h PositionHistory
+	id := uuid.New()
+	aabb := testPlayerAABB
+
+	// Walking at 5 m/s, sending positions every tick
+	for i := 0; i <= 20; i++ {
+		tm := time.Duration(i) * time.Second / TICK_RATE
+		h.Record(id, tm, mgl32.Vec3{float32(tm.Seconds() * 5), 0, 0}, aabb)
+	}
+	now := time.Second
+
+	tests := []struct {
+		rtt  time.Duration
+		want float32
+	}{
+		// Between two records
+		{25 * ms, float32((now - 25*ms - INTERPOLATION_DELAY).Seconds() * 5)},
+		{160 * ms, float32((now - 160*ms - INTERPOLATION_DELAY).Seconds() * 5)},
+		{time.Second, float32((now - MAX_REWIND).Seconds() * 5)},
+	}
+	for _, v := range tests {
+		pos, gotAABB, ok := h.Rewind(id, ViewTime(now, v.rtt))
+		if !ok || !pos.ApproxEqualThreshold(mgl32.Vec3{v.want, 0, 0}, 1e-4) || gotAABB != aabb {
+			t.Errorf("rewinding with RTT %v should be at x = %v, got %v", v.rtt, v.want, pos)
+		}
+	}
+
+	// Before and after the records
+	if pos, _, _ := h.Rewind(id, -time.Second); pos.X() != 0 {
+		t.Errorf("rewinding before the first record should give it, got %v", pos)
+	}
+	if pos, _, _ := h.Rewind(id, 2*time.Second); pos.X() != 5 {
+		t.Errorf("rewinding after the last record should give it, got %v", pos)
+	}
+	if _, _, ok := h.Rewind(uuid.New(), now); ok {
+		t.Error("rewinding an unknown entity should fail")
+	}
+}
+
+func TestRewindSameTime(t *testing.T) {
+	var h PositionHistory
+	id := uuid.New()
+
+	// Two positions arriving in the same tick
+	h.Record(id, 0, mgl32.Vec3{0, 0, 0}, testPlayerAABB)
+	h.Record(id, 50*ms, mgl32.Vec3{1, 0, 0}, testPlayerAABB)
+	h.Record(id, 50*ms, mgl32.Vec3{2, 0, 0}, testPlayerAABB)
+
+	for _, tm := range []time.Duration{25 * ms, 50 * ms, 60 * ms} {
+		pos, _, _ := h.Rewind(id, tm)
+		for _, v := range pos {
+			if v != v {
+				t.Fatalf("rewinding to %v gave NaN", tm)
+			}
+		}
+	}
+	if pos, _, _ := h.Rewind(id, 50*ms); pos.X() != 2 {
+		t.Errorf("the later record at the same time should replace the first, got %v", pos)
+	}
+}
@@ -1,47 +1,206 @@
 package core
 
 import (
+	"math"
 	"time"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// How far in the past remote entities are shown, so there is usually a
+// snapshot either side of the time being shown, even if one is late
+const INTERPOLATION_DELAY = 100 * time.Millisecond
+
+// How far past the last snapshot an entity keeps moving when snapshots stop
+// arriving, such as when packets are lost. It then stops until the next one.
+const MAX_EXTRAPOLATION = 250 * time.Millisecond
+
+// The most snapshots kept of each entity
+const MAX_SNAPSHOTS = 32
+
+// Clock offsets further than this from the estimate are jumped to, rather
+// than smoothed towards, such as when first joining
+const CLOCK_RESYNC_THRESHOLD = time.Second
+
+// Where an entity was at a time on the server
+type Snapshot struct {
+	// Since the server started
+	Time     time.Duration
+	Position mgl32.Vec3
+
+	Yaw       float64
+	Azimuth   float64
+	Elevation float64
+}
+
 type LerpFace interface {
 	GetLerpComp() *LerpComp
 }
 
+// Moves an entity between the snapshots sent by the server, instead of
+// jumping to each one as it arrives
 type LerpComp struct {
-	lerpStartPos  mgl32.Vec3
-	lerpEndPos    mgl32.Vec3
-	lerpEndTime   time.Time
-	lerpStartTime time.Time
+	// Oldest first
+	snapshots []Snapshot
 }
 
 func (p *LerpComp) GetLerpComp() *LerpComp {
 	return p
 }
 
-func (e *LerpComp) NewLerp(end mgl32.Vec3) {
-	e.lerpStartPos = e.lerpEndPos
-	e.lerpStartTime = e.lerpEndTime
-	e.lerpEndPos = end
-	e.lerpEndTime = time.Now()
+// Adds a snapshot of the entity from the server. Snapshots arriving out of
+// order are put in order, and those with the same time replace each other.
+func (e *LerpComp) AddSnapshot(s Snapshot) {
+	i := len(e.snapshots)
+	for i > 0 && e.snapshots[i-1].Time > s.Time {
+		i--
+	}
+
+	if i > 0 && e.snapshots[i-1].Time == s.Time {
+		e.snapshots[i-1] = s
+		return
+	}
+
+	e.snapshots = append(e.snapshots, Snapshot{})
+	copy(e.snapshots[i+1:], e.snapshots[i:])
+	e.snapshots[i] = s
+
+	if len(e.snapshots) > MAX_SNAPSHOTS {
+		e.snapshots = e.snapshots[len(e.snapshots)-MAX_SNAPSHOTS:]
+	}
+}
+
+// Returns where the entity was at the server time, interpolating between the
+// snapshots either side of it. Past the last snapshot, the entity carries on
+// at its last velocity for up to MAX_EXTRAPOLATION. Returns false if there are
+// no snapshots.
+// Snapshots older than the time are dropped, so t shouldn't go backwards.
+func (e *LerpComp) Sample(t time.Duration) (Snapshot, bool) {
+	if len(e.snapshots) == 0 {
+		return Snapshot{}, false
+	}
+
+	// Keep the last snapshot before t, which is needed to interpolate
+	drop := 0
+	for drop+1 < len(e.snapshots) && e.snapshots[drop+1].Time <= t {
+		drop++
+	}
+	if drop > 0 && drop == len(e.snapshots)-1 {
+		// Extrapolating needs the snapshot before the last
+		drop--
+	}
+	e.snapshots = e.snapshots[drop:]
+
+	first := e.snapshots[0]
+	if t <= first.Time || len(e.snapshots) == 1 {
+		return first, true
+	}
+
+	if t <= e.snapshots[1].Time {
+		return lerpSnapshots(first, e.snapshots[1], t), true
+	}
+
+	// Ran out of snapshots, so extrapolate from the last two
+	last := e.snapshots[1]
+	if t > last.Time+MAX_EXTRAPOLATION {
+		t = last.Time + MAX_EXTRAPOLATION
+	}
+	s := lerpSnapshots(first, last, t)
+	// Turning is too unpredictable to guess
+	s.Yaw, s.Azimuth, s.Elevation = last.Yaw, last.Azimuth, last.Elevation
+	return s, true
+}
+
+// Interpolates between the snapshots, or extrapolates past b if t is after it
+func lerpSnapshots(a, b Snapshot, t time.Duration) Snapshot {
+	if b.Time <= a.Time {
+		return b
+	}
+
+	s := float32(t-a.Time) / float32(b.Time-a.Time)
+	return Snapshot{
+		Time:      t,
+		Position:  a.Position.Add(b.Position.Sub(a.Position).Mul(s)),
+		Yaw:       lerpAngle(a.Yaw, b.Yaw, float64(s)),
+		Azimuth:   lerpAngle(a.Azimuth, b.Azimuth, float64(s)),
+		Elevation: a.Elevation + (b.Elevation-a.Elevation)*float64(s),
+	}
+}
+
+// Interpolates between the angles in radians, the shortest way around
+func lerpAngle(a, b, s float64) float64 {
+	diff := math.Mod(b-a, 2*math.Pi)
+	if diff > math.Pi {
+		diff -= 2 * math.Pi
+	} else if diff < -math.Pi {
+		diff += 2 * math.Pi
+	}
+	return a + diff*s
+}
+
+// Estimates the server's time from the times of the snapshots it sends, to
+// know which time to show remote entities at
+type InterpolationClock struct {
+	// Returns the local time. Can be replaced, such as by a fake clock in tests
+	Now func() time.Duration
+
+	// The server time minus the local time
+	offset time.Duration
+	synced bool
+}
+
+// Creates a clock which uses the time since it was created
+func NewInterpolationClock() *InterpolationClock {
+	start := time.Now()
+	return &InterpolationClock{
+		Now: func() time.Duration {
+			return time.Since(start)
+		},
+	}
+}
+
+// Updates the estimate of the server's time with the time of a snapshot which
+// has just arrived. The estimate moves slowly, so the jitter of packets
+// doesn't make entities jitter.
+func (c *InterpolationClock) Observe(serverTime time.Duration) {
+	offset := serverTime - c.Now()
+
+	diff := offset - c.offset
+	if !c.synced || diff > CLOCK_RESYNC_THRESHOLD || diff < -CLOCK_RESYNC_THRESHOLD {
+		c.offset = offset
+		c.synced = true
+		return
+	}
+
+	c.offset += diff / 10
+}
+
+// The server time remote entities should be shown at
+func (c *InterpolationClock) RenderTime() time.Duration {
+	return c.Now() + c.offset - INTERPOLATION_DELAY
 }
 
-func LerpSystem(dim *Dimension) {
-	dynamic := GetEntitiesSatisfying[interface {
+// Moves entities with a LerpComp to where they were at the clock's render time
+func LerpSystem(dim *Dimension, clock *InterpolationClock) {
+	t := clock.RenderTime()
+
+	for _, v := range Query[interface {
+		Entity
 		LerpFace
 		PositionFace
-	}](dim.Entities)
-
-	for _, v := range dynamic {
-		e := v.GetLerpComp()
-		pos := v.GetPosition()
+	}](&dim.Entities) {
+		s, ok := v.GetLerpComp().Sample(t)
+		if !ok {
+			continue
+		}
 
-		lerpDelta := e.lerpEndTime.Sub(e.lerpStartTime)
-		scalar := time.Since(e.lerpEndTime) / lerpDelta
+		*v.GetPosition() = s.Position
+		dim.Entities.Moved(v)
 
-		dir := e.lerpEndPos.Sub(e.lerpStartPos)
-		*pos = dir.Mul(float32(scalar)).Add(e.lerpStartPos)
+		if l, ok := v.(LookFace); ok {
+			l.GetLookComp().Yaw = s.Yaw
+			l.GetLookComp().Azimuth = s.Azimuth
+			l.GetLookComp().Elevation = s.Elevation
+		}
 	}
 }
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const ms = time.Millisecond
+
+func checkPosition(t *testing.T, e *LerpComp, at time.Duration, want mgl32.Vec3) {
+	t.Helper()
+
+	s, ok := e.Sample(at)
+	if !ok {
+		t.Fatalf("no snapshot at %v", at)
+	}
+	if !s.Position.ApproxEqualThreshold(want, 1e-4) {
+		t.Errorf("position at %v should be %v, got %v", at, want, s.Position)
+	}
+}
+
+func TestLerpOutOfOrder(t *testing.T) {
+	var e LerpComp
+	e.AddSnapshot(Snapshot{Time: 100 * ms, Position: mgl32.Vec3{1, 0, 0}})
+	e.AddSnapshot(Snapshot{Time: 0, Position: mgl32.Vec3{0, 0, 0}})
+	e.AddSnapshot(Snapshot{Time: 200 * ms, Position: mgl32.Vec3{2, 0, 0}})
+	// A late duplicate replaces the first
+	e.AddSnapshot(Snapshot{Time: 100 * ms, Position: mgl32.Vec3{1, 1, 0}})
+
+	checkPosition(t, &e, 50*ms, mgl32.Vec3{0.5, 0.5, 0})
+	checkPosition(t, &e, 150*ms, mgl32.Vec3{1.5, 0.5, 0})
+	checkPosition(t, &e, 200*ms, mgl32.Vec3{2, 0, 0})
+}
+
+func TestLerpAngleWraparound(t *testing.T) {
+	var e LerpComp
+	e.AddSnapshot(Snapshot{Time: 0, Yaw: 2*math.Pi - 0.1, Azimuth: -math.Pi + 0.1})
+	e.AddSnapshot(Snapshot{Time: 100 * ms, Yaw: 0.1, Azimuth: math.Pi - 0.1})
+
+	s, _ := e.Sample(50 * ms)
+	// Turning the short way, through 0 and through pi
+	if d := math.Remainder(s.Yaw, 2*math.Pi); math.Abs(d) > 1e-6 {
+		t.Errorf("yaw should turn through 0, got %v", s.Yaw)
+	}
+	if d := math.Remainder(s.Azimuth-math.Pi, 2*math.Pi); math.Abs(d) > 1e-6 {
+		t.Errorf("azimuth should turn through pi, got %v", s.Azimuth)
+	}
+
+	s, _ = e.Sample(75 * ms)
+	if d := math.Remainder(s.Yaw-0.05, 2*math.Pi); math.Abs(d) > 1e-6 {
+		t.Errorf("yaw should be 0.05 three quarters of the way, got %v", s.Yaw)
+	}
+}
+
+func TestLerpExtrapolationLimit(t *testing.T) {
+	var e LerpComp
+	// Moving at 10 m/s, then snapshots stop
+	e.AddSnapshot(Snapshot{Time: 0, Position: mgl32.Vec3{0, 0, 0}, Yaw: 0})
+	e.AddSnapshot(Snapshot{Time: 100 * ms, Position: mgl32.Vec3{1, 0, 0}, Yaw: 1})
+
+	checkPosition(t, &e, 200*ms, mgl32.Vec3{2, 0, 0})
+
+	stop := 100*ms + MAX_EXTRAPOLATION
+	want := mgl32.Vec3{float32(stop.Seconds() * 10), 0, 0}
+	checkPosition(t, &e, stop, want)
+	checkPosition(t, &e, 5*time.Second, want)
+
+	if s, _ := e.Sample(5 * time.Second); s.Yaw != 1 {
+		t.Errorf("turning shouldn't be extrapolated, got yaw %v", s.Yaw)
+	}
+
+	// Snapshots arriving again are interpolated to as normal
+	e.AddSnapshot(Snapshot{Time: 6 * time.Second, Position: mgl32.Vec3{5, 0, 0}})
+	checkPosition(t, &e, 6*time.Second, mgl32.Vec3{5, 0, 0})
+}
+
+func TestInterpolationClockResync(t *testing.T) {
+	var now time.Duration
+	c := &InterpolationClock{Now: func() time.Duration { return now }}
+
+	check := func(want time.Duration) {
+		t.Helper()
+		if got := c.RenderTime(); got != want {
+			t.Errorf("render time should be %v, got %v", want, got)
+		}
+	}
+
+	// The first snapshot sets the offset
+	c.Observe(10 * time.Second)
+	check(10*time.Second - INTERPOLATION_DELAY)
+
+	// Jitter is smoothed out
+	now = time.Second
+	c.Observe(11*time.Second + 200*ms)
+	check(11*time.Second + 20*ms - INTERPOLATION_DELAY)
+
+	// Just within the threshold is still smoothed
+	now = 2 * time.Second
+	c.Observe(12*time.Second + 20*ms + CLOCK_RESYNC_THRESHOLD)
+	check(12*time.Second + 20*ms + CLOCK_RESYNC_THRESHOLD/10 - INTERPOLATION_DELAY)
+
+	// Past the threshold jumps, such as after the server pauses
+	now = 3 * time.Second
+	c.Observe(20 * time.Second)
+	check(20*time.Second - INTERPOLATION_DELAY)
+	c.Observe(20*time.Second - 2*CLOCK_RESYNC_THRESHOLD)
+	check(20*time.Second - 2*CLOCK_RESYNC_THRESHOLD - INTERPOLATION_DELAY)
+}
@@ -36,3 +36,17 @@ func FlooredRemainder(x int, y int) int {
 func RoundThenFloor(f float32) float32 {
 	return float32(math.Floor(float64(mgl32.Round(f, 3))))
 }
+
+// The unit vector pointing in the direction of the look angles, in radians
+func LookDirection(azimuth, elevation float64) mgl32.Vec3 {
+	return mgl32.Vec3{
+		float32(math.Cos(elevation) * math.Sin(azimuth)),
+		float32(math.Sin(elevation)),
+		float32(math.Cos(elevation) * math.Cos(azimuth)),
+	}
+}
+
+// The look angles of the unit vector, in radians
+func DirectionToLook(dir mgl32.Vec3) (azimuth, elevation float64) {
+	return math.Atan2(float64(dir.X()), float64(dir.Z())), math.Asin(float64(dir.Y()))
+}
@@ -0,0 +1,80 @@
+package core
+
+import "time"
+
+// How long the animations last on clients
+const (
+	SWING_ANIMATION_TIME = 300 * time.Millisecond
+	HURT_ANIMATION_TIME  = 500 * time.Millisecond
+)
+
+// A one-off animation played by an entity, which isn't part of its state
+type EntityAnimation int
+
+const (
+	// Swings the held item, such as when attacking or digging
+	AnimationSwing EntityAnimation = iota
+	// Flashes red after taking damage
+	AnimationHurt
+)
+
+// The state of an entity which other players can see, but which isn't part of
+// its position. Must stay comparable, so the server can check for changes.
+type EntityMetadata struct {
+	// The item type held in the main hand, or empty for none
+	HeldItem string
+	// TODO Worn armour, once there are armour items and slots to wear them in
+
+	Sneaking  bool
+	Sprinting bool
+}
+
+type MetadataFace interface {
+	GetMetadataComp() *MetadataComp
+}
+
+type MetadataComp struct {
+	Metadata EntityMetadata
+
+	// When the animations were last played, only used by clients
+	swingStart time.Time
+	hurtStart  time.Time
+}
+
+func (m *MetadataComp) GetMetadataComp() *MetadataComp {
+	return m
+}
+
+// Starts playing the animation
+func (m *MetadataComp) PlayAnimation(a EntityAnimation) {
+	switch a {
+	case AnimationSwing:
+		m.swingStart = time.Now()
+	case AnimationHurt:
+		m.hurtStart = time.Now()
+	}
+}
+
+// How far through the swing animation the entity is, from 0 to 1, or false if
+// it isn't swinging
+func (m *MetadataComp) SwingProgress() (float32, bool) {
+	return animationProgress(m.swingStart, SWING_ANIMATION_TIME)
+}
+
+// Whether the entity was hurt recently, so should be tinted red
+func (m *MetadataComp) Hurt() bool {
+	_, ok := animationProgress(m.hurtStart, HURT_ANIMATION_TIME)
+	return ok
+}
+
+func animationProgress(start time.Time, length time.Duration) (float32, bool) {
+	if start.IsZero() {
+		return 0, false
+	}
+
+	t := time.Since(start)
+	if t >= length {
+		return 0, false
+	}
+	return float32(t) / float32(length), true
+}
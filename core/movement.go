@@ -0,0 +1,252 @@
+package core
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The vertical speed when flying, in m/s
+const FLYING_VERTICAL_SPEED = 7.5
+
+// The vertical speed players and mobs jump at, in m/s
+const JUMP_SPEED = 8.4
+
+// What a player did in a tick, which is all that is needed to simulate the
+// tick. Clients send it with their position, and the server runs the same
+// movement to check it.
+type PlayerInput struct {
+	Forward, Back, Left, Right bool
+	// Space and left shift
+	Jump, Sneak bool
+	Sprint      bool
+	// Set when jump was double tapped
+	ToggleFlying bool
+
+	// The direction the player was facing
+	Azimuth float64
+
+	// Set when the player can't move, such as when dead or in a container
+	Idle bool
+
+	// The number of knockbacks from the server applied before the tick
+	Knockback int
+}
+
+type MovementFace interface {
+	GetMovementComp() *MovementComp
+}
+
+// How a player is moving, which is changed by their inputs
+type MovementComp struct {
+	GameMode  GameMode
+	Flying    bool
+	Sneaking  bool
+	Sprinting bool
+
+	// Multiplies how fast the player walks
+	Speed float64
+}
+
+func NewMovementComp() MovementComp {
+	return MovementComp{Speed: 1}
+}
+
+func (m *MovementComp) GetMovementComp() *MovementComp {
+	return m
+}
+
+// A player's body, which is moved by their inputs, on both the client and the
+// server
+type PlayerBody interface {
+	Body
+	MovementFace
+	HungerFace
+}
+
+func SetPlayerSneaking(p PlayerBody, b bool) {
+	e := p.GetPhysicsComp()
+	p.GetMovementComp().Sneaking = b
+	e.StayOnEdges = b
+	if b {
+		e.AABB = PLAYER_SNEAKING_AABB
+	} else {
+		e.AABB = PLAYER_AABB
+	}
+}
+
+func SetPlayerFlying(p PlayerBody, b bool) {
+	e := p.GetPhysicsComp()
+	p.GetMovementComp().Flying = b
+	e.NoGravity = b
+	if b {
+		e.Velocity[1] = 0
+	}
+}
+
+// Changes the game mode, and what the player's body can do in it
+func SetPlayerGameMode(p PlayerBody, g GameMode) {
+	p.GetMovementComp().GameMode = g
+	p.GetPhysicsComp().NoClip = g.NoClip()
+
+	// Spectators are always flying
+	SetPlayerFlying(p, g == Spectator)
+}
+
+// Players without enough food can't sprint, unless hunger doesn't apply to them
+func canPlayerSprint(p PlayerBody) bool {
+	return p.GetHungerComp().CanSprint() || !p.GetMovementComp().GameMode.TakesDamage()
+}
+
+// Moves the player for a tick from the input, returning whether they jumped.
+// Knockback isn't applied, as only the client and server know what it was.
+// Run before StepBody, on the client to predict the player's movement and
+// replay it after corrections, and on the server to check it.
+func ApplyPlayerInput(p PlayerBody, in PlayerInput) (jumping bool) {
+	e := p.GetPhysicsComp()
+	m := p.GetMovementComp()
+
+	// Slows the player down when they aren't walking
+	if in.Idle {
+		e.ApplyFriction()
+		return false
+	}
+
+	// Flying is toggled by double tapping jump
+	if in.ToggleFlying && m.GameMode == Creative {
+		SetPlayerFlying(p, !m.Flying)
+	}
+
+	// Creative players stop flying when they land
+	if m.Flying && e.OnGround() && m.GameMode != Spectator {
+		SetPlayerFlying(p, false)
+	}
+
+	// Swim up, or jump
+	fluid := e.InFluid()
+	if in.Jump && fluid != nil && !m.Flying {
+		e.Velocity[1] += fluid.SwimAcceleration / TICK_RATE
+	} else if in.Jump && e.OnGround() && !m.Flying {
+		// TODO Add timeout to next jump
+		e.Velocity[1] = JUMP_SPEED
+		jumping = true
+	}
+
+	// Fly up and down
+	if m.Flying {
+		e.Velocity[1] = 0
+		if in.Jump {
+			e.Velocity[1] += FLYING_VERTICAL_SPEED
+		}
+		if in.Sneak {
+			e.Velocity[1] -= FLYING_VERTICAL_SPEED
+		}
+	}
+
+	// Sneak, which isn't possible while flying
+	if in.Sneak && !m.Flying {
+		// Nested for a reason
+		if !m.Sneaking {
+			SetPlayerSneaking(p, true)
+		}
+	} else if m.Sneaking {
+		SetPlayerSneaking(p, false)
+	}
+
+	// Sprint
+	if in.Sprint && !m.Sprinting && canPlayerSprint(p) {
+		m.Sprinting = true
+	}
+
+	var walkVec mgl32.Vec2
+
+	// Move forwards
+	if in.Forward {
+		walkVec[0] += 1
+	}
+	// Move backwards
+	if in.Back {
+		walkVec[0] += -1
+	}
+	// Move right
+	if in.Right {
+		walkVec[1] += 1
+	}
+	// Move left
+	if in.Left {
+		walkVec[1] += -1
+	}
+
+	if (walkVec.X() == 0 || m.Sneaking || !canPlayerSprint(p)) && m.Sprinting {
+		m.Sprinting = false
+	}
+
+	// Procees horizontal velocity according to
+	// https://www.mcpk.wiki/wiki/Horizontal_Movement_Formulas
+	moveMult := 1.0
+	if m.Sprinting {
+		moveMult = 1.3
+	} else if m.Sneaking {
+		moveMult = 0.3
+	} else if walkVec.X() == 0 && walkVec.Y() == 0 {
+		moveMult = 0
+	}
+	if walkVec.X() == 0 || walkVec.Y() == 0 {
+		moveMult *= 0.98
+	} else if m.Sneaking {
+		moveMult *= 0.98 * math.Sqrt(2)
+	}
+
+	slipperiness := float64(e.Slipperiness())
+	groundAccel := float32(moveMult*m.Speed*math.Pow(DEFAULT_SLIPPERINESS/slipperiness, 3)) * 0.1 * 20
+	direction := in.Azimuth
+	if moveMult != 0 {
+		if walkVec.X() == 0 {
+			if walkVec.Y() < 0 {
+				direction += math.Pi / 2
+			} else {
+				direction -= math.Pi / 2
+			}
+		} else {
+			direction -= math.Atan(float64(walkVec.Y() / walkVec.X()))
+		}
+	}
+	if walkVec.X() < 0 {
+		direction += math.Pi
+	}
+
+	// Fluids slow the player down instead of friction
+	if fluid == nil || m.Flying {
+		e.ApplyFriction()
+	}
+
+	if fluid != nil && !m.Flying {
+		// Swim
+		// TODO Sprint swimming
+		e.Velocity[0] += float32(0.02 * moveMult * math.Sin(direction) * 20)
+		e.Velocity[2] += float32(0.02 * moveMult * math.Cos(direction) * 20)
+
+	} else if jumping {
+		e.Velocity[0] += groundAccel * float32(math.Sin(direction))
+		e.Velocity[2] += groundAccel * float32(math.Cos(direction))
+
+		if m.Sprinting {
+			e.Velocity[0] += 0.2 * float32(math.Sin(in.Azimuth)) * 20 * 0.91 * 0.6
+			e.Velocity[2] += 0.2 * float32(math.Cos(in.Azimuth)) * 20 * 0.91 * 0.6
+		}
+
+	} else if e.OnGround() {
+		e.Velocity[0] += groundAccel * float32(math.Sin(direction))
+		e.Velocity[2] += groundAccel * float32(math.Cos(direction))
+
+	} else {
+		airAccel := 0.02
+		if m.Flying {
+			airAccel = 0.05
+		}
+		e.Velocity[0] += float32(airAccel * moveMult * math.Sin(direction) * 20)
+		e.Velocity[2] += float32(airAccel * moveMult * math.Cos(direction) * 20)
+	}
+
+	return jumping
+}
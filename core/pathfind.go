@@ -0,0 +1,371 @@
+package core
+
+import (
+	"container/heap"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type PathOptions struct {
+	// The size of the entity. Every block it passes through must have space
+	// for the whole AABB, rounded up to whole blocks.
+	AABB mgl32.Vec3
+	// The most blocks the entity can climb in one step
+	MaxJump int
+	// The most blocks the entity will drop down in one step
+	MaxDrop int
+	// The most nodes which will be searched before giving up. The path to the
+	// node closest to the goal is returned instead.
+	MaxNodes int
+}
+
+func NewPathOptions(aabb mgl32.Vec3) PathOptions {
+	return PathOptions{
+		AABB:     aabb,
+		MaxJump:  1,
+		MaxDrop:  3,
+		MaxNodes: 1000,
+	}
+}
+
+// The extra cost of moving up or down a block, on top of moving across one
+const (
+	pathJumpCost = 0.5
+	pathDropCost = 0.2
+)
+
+// The directions a path can move in, in the order they are searched
+// TODO Diagonals
+var pathDirections = []Vec3{
+	{X: 1}, {X: -1}, {Z: 1}, {Z: -1},
+}
+
+// A route through the blocks of a dimension
+type Path struct {
+	// The positions of the entity along the path, with the first being where
+	// it started. The position of the entity is the block its feet are in,
+	// at the corner with the smallest coordinates.
+	Nodes []Vec3
+	// Whether the goal could not be reached, in which case the path leads to
+	// the node that got the closest
+	Partial bool
+
+	// The blocks which the path needs to stay the same to be walkable
+	blocks  map[Vec3]struct{}
+	invalid bool
+	dim     *Dimension
+}
+
+// Whether the path can still be walked. A path is invalidated when any block
+// it goes through, or stands on, is changed with SetBlockAt.
+func (p *Path) Valid() bool {
+	return !p.invalid
+}
+
+// Stops checking for changes to the blocks of the path. This should be called
+// when the path isn't needed anymore, and is safe to call on a nil path.
+// You must lock Dim yourself
+func (p *Path) Release() {
+	if p == nil || p.dim == nil {
+		return
+	}
+	for v := range p.blocks {
+		delete(p.dim.paths[v], p)
+		if len(p.dim.paths[v]) == 0 {
+			delete(p.dim.paths, v)
+		}
+	}
+	p.dim = nil
+}
+
+// Registers the path with the dimension under each of its blocks
+func (d *Dimension) addPath(p *Path) {
+	if d.paths == nil {
+		d.paths = make(map[Vec3]map[*Path]struct{})
+	}
+	for v := range p.blocks {
+		if d.paths[v] == nil {
+			d.paths[v] = make(map[*Path]struct{})
+		}
+		d.paths[v][p] = struct{}{}
+	}
+}
+
+// Invalidates all paths which depend on the block
+// You must lock Dim yourself
+func (d *Dimension) invalidatePaths(pos Vec3) {
+	for p := range d.paths[pos] {
+		p.invalid = true
+		p.Release()
+	}
+}
+
+type pathNode struct {
+	pos    Vec3
+	parent *pathNode
+	// The cost from the start, and the estimated cost to the goal
+	g, h float32
+	// The order the node was found in, so ties are always broken the same way
+	seq int
+
+	closed bool
+}
+
+type pathHeap []*pathNode
+
+func (h pathHeap) Len() int { return len(h) }
+
+func (h pathHeap) Less(i, j int) bool {
+	fi, fj := h[i].g+h[i].h, h[j].g+h[j].h
+	if fi != fj {
+		return fi < fj
+	}
+	if h[i].h != h[j].h {
+		return h[i].h < h[j].h
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h pathHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *pathHeap) Push(x any) { *h = append(*h, x.(*pathNode)) }
+
+func (h *pathHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+type pathfinder struct {
+	dim  *Dimension
+	opts PathOptions
+	// The size of the entity in blocks
+	width, height int
+}
+
+func newPathfinder(dim *Dimension, opts PathOptions) pathfinder {
+	return pathfinder{
+		dim:    dim,
+		opts:   opts,
+		width:  pathWidth(opts.AABB),
+		height: CeilFloat32(opts.AABB.Y()),
+	}
+}
+
+// The number of blocks across an entity with the AABB takes up
+func pathWidth(aabb mgl32.Vec3) int {
+	if aabb.Z() > aabb.X() {
+		return CeilFloat32(aabb.Z())
+	}
+	return CeilFloat32(aabb.X())
+}
+
+// Calls fn for every block the entity takes up at the position
+func (f pathfinder) body(pos Vec3, fn func(Vec3)) {
+	for x := 0; x < f.width; x++ {
+		for y := 0; y < f.height; y++ {
+			for z := 0; z < f.width; z++ {
+				fn(pos.Add(NewVec3(x, y, z)))
+			}
+		}
+	}
+}
+
+// Calls fn for every block the entity could stand on at the position
+func (f pathfinder) floor(pos Vec3, fn func(Vec3)) {
+	for x := 0; x < f.width; x++ {
+		for z := 0; z < f.width; z++ {
+			fn(pos.Add(NewVec3(x, -1, z)))
+		}
+	}
+}
+
+// Whether the entity has space to be at the position
+func (f pathfinder) fits(pos Vec3) bool {
+	ok := true
+	f.body(pos, func(v Vec3) {
+		if ok && f.dim.GetBlockAt(v).IsSolid() {
+			ok = false
+		}
+	})
+	return ok
+}
+
+// Whether the entity fits at the position, and has something to stand on
+func (f pathfinder) standable(pos Vec3) bool {
+	if !f.fits(pos) {
+		return false
+	}
+
+	ok := false
+	f.floor(pos, func(v Vec3) {
+		if !ok && f.dim.GetBlockAt(v).IsSolid() {
+			ok = true
+		}
+	})
+	return ok
+}
+
+// Whether an entity with the AABB has space to be at the position, and
+// something to stand on
+// You must lock Dim yourself
+func CanStandAt(dim *Dimension, pos Vec3, aabb mgl32.Vec3) bool {
+	return newPathfinder(dim, NewPathOptions(aabb)).standable(pos)
+}
+
+// Finds the position the entity can move to from pos in the direction,
+// either on the same level, by jumping up, or by dropping down
+func (f pathfinder) step(pos, dir Vec3) (Vec3, float32, bool) {
+	next := pos.Add(dir)
+
+	if f.fits(next) {
+		for d := 0; d <= f.opts.MaxDrop; d++ {
+			v := next.Add(Vec3{Y: -d})
+			if !f.fits(v) {
+				break
+			}
+			if f.standable(v) {
+				return v, 1 + float32(d)*pathDropCost, true
+			}
+		}
+		return Vec3{}, 0, false
+	}
+
+	for j := 1; j <= f.opts.MaxJump; j++ {
+		// There must be room to jump up before moving across
+		if !f.fits(pos.Add(Vec3{Y: j})) {
+			break
+		}
+
+		v := next.Add(Vec3{Y: j})
+		if f.standable(v) {
+			return v, 1 + float32(j)*pathJumpCost, true
+		}
+	}
+	return Vec3{}, 0, false
+}
+
+// Only the horizontal distance is used, as every step moves exactly one block
+// across, so the estimate never overshoots.
+func pathHeuristic(pos, goal Vec3) float32 {
+	dx, dz := pos.X-goal.X, pos.Z-goal.Z
+	if dx < 0 {
+		dx = -dx
+	}
+	if dz < 0 {
+		dz = -dz
+	}
+	return float32(dx + dz)
+}
+
+// Finds a route for an entity from start to goal using A*, walking along the
+// ground. If the goal can't be reached within the node budget, a partial path
+// to the closest node found is returned. The search is deterministic, so the
+// same dimension will always give the same path.
+//
+// The path is registered with the dimension, so that it is invalidated when a
+// block on it changes. Call Release once it is no longer used.
+// You must lock Dim for writing yourself
+func FindPath(dim *Dimension, start, goal Vec3, opts PathOptions) *Path {
+	f := newPathfinder(dim, opts)
+
+	seq := 0
+	first := &pathNode{pos: start, h: pathHeuristic(start, goal)}
+	nodes := map[Vec3]*pathNode{start: first}
+	open := &pathHeap{first}
+	closest := first
+
+	var end *pathNode
+	searched := 0
+	for open.Len() > 0 && searched < opts.MaxNodes {
+		n := heap.Pop(open).(*pathNode)
+		if n.closed || nodes[n.pos] != n {
+			// A cheaper way to the position was found after this was queued
+			continue
+		}
+		n.closed = true
+		searched++
+
+		if n.h < closest.h || (n.h == closest.h && n.g < closest.g) {
+			closest = n
+		}
+		if n.pos == goal {
+			end = n
+			break
+		}
+
+		for _, dir := range pathDirections {
+			pos, cost, ok := f.step(n.pos, dir)
+			if !ok {
+				continue
+			}
+
+			g := n.g + cost
+			if old, ok := nodes[pos]; ok && (old.closed || old.g <= g) {
+				continue
+			}
+
+			seq++
+			next := &pathNode{pos: pos, parent: n, g: g, h: pathHeuristic(pos, goal), seq: seq}
+			nodes[pos] = next
+			heap.Push(open, next)
+		}
+	}
+
+	p := &Path{Partial: end == nil, blocks: make(map[Vec3]struct{}), dim: dim}
+	if end == nil {
+		end = closest
+	}
+	for n := end; n != nil; n = n.parent {
+		p.Nodes = append(p.Nodes, n.pos)
+	}
+	for i, j := 0, len(p.Nodes)-1; i < j; i, j = i+1, j-1 {
+		p.Nodes[i], p.Nodes[j] = p.Nodes[j], p.Nodes[i]
+	}
+
+	f.addDependencies(p)
+	dim.addPath(p)
+
+	return p
+}
+
+// Records every block the path passes through or stands on, including the
+// space needed to jump up or drop down between nodes
+func (f pathfinder) addDependencies(p *Path) {
+	add := func(v Vec3) {
+		p.blocks[v] = struct{}{}
+	}
+
+	for i, n := range p.Nodes {
+		f.body(n, add)
+		f.floor(n, add)
+
+		if i == 0 {
+			continue
+		}
+		prev := p.Nodes[i-1]
+		low, high := prev.Y, n.Y
+		if low > high {
+			low, high = high, low
+		}
+		for y := low; y <= high; y++ {
+			f.body(NewVec3(prev.X, y, prev.Z), add)
+			f.body(NewVec3(n.X, y, n.Z), add)
+		}
+	}
+}
+
+// The node an entity with the AABB is at, for an entity at the position
+func PathNodeAt(pos, aabb mgl32.Vec3) Vec3 {
+	// Centre the entity in the blocks it takes up
+	width := float32(pathWidth(aabb))
+	centre := pos.Add(mgl32.Vec3{aabb.X() / 2, 0, aabb.Z() / 2})
+	return NewVec3FromFloat(centre.Sub(mgl32.Vec3{width / 2, 0, width / 2}).Add(mgl32.Vec3{0.5, 0, 0.5}))
+}
+
+// The centre of the bottom of the space an entity with the AABB takes up at the node
+func PathNodeCentre(node Vec3, aabb mgl32.Vec3) mgl32.Vec3 {
+	width := float32(pathWidth(aabb))
+	return node.ToFloat().Add(mgl32.Vec3{width / 2, 0, width / 2})
+}
@@ -0,0 +1,118 @@
+package core
+
+import "testing"
+
+// A flat floor to walk on at y = 0, so paths are at y = 1
+func newTestFloor() *Dimension {
+	d := newTestDimension()
+	fillTestBlocks(d, NewVec3(-8, 0, -8), NewVec3(24, 0, 8), testStone)
+	return d
+}
+
+func TestFindPathAroundWall(t *testing.T) {
+	d := newTestFloor()
+	// Too tall to jump over
+	wall := func(v Vec3) bool { return v.X == 5 && v.Z >= -3 && v.Z <= 3 }
+	fillTestBlocks(d, NewVec3(5, 1, -3), NewVec3(5, 3, 3), testStone)
+
+	start, goal := NewVec3(0, 1, 0), NewVec3(10, 1, 0)
+	p := FindPath(d, start, goal, NewPathOptions(testPlayerAABB))
+	defer p.Release()
+
+	if p.Partial {
+		t.Fatal("path should reach the goal")
+	}
+	if p.Nodes[0] != start || p.Nodes[len(p.Nodes)-1] != goal {
+		t.Fatalf("path should go from %v to %v, got %v", start, goal, p.Nodes)
+	}
+	for i, v := range p.Nodes {
+		if wall(v) {
+			t.Fatalf("path goes through the wall at %v", v)
+		}
+		if i > 0 && pathHeuristic(v, p.Nodes[i-1]) != 1 {
+			t.Fatalf("path jumps from %v to %v", p.Nodes[i-1], v)
+		}
+	}
+	// Going straight would take 10 steps
+	if len(p.Nodes)-1 <= 10 {
+		t.Fatalf("path should detour around the wall, got %v", p.Nodes)
+	}
+}
+
+func TestFindPathStepUp(t *testing.T) {
+	d := newTestFloor()
+	fillTestBlocks(d, NewVec3(3, 1, -8), NewVec3(24, 1, 8), testStone)
+
+	goal := NewVec3(6, 2, 0)
+	p := FindPath(d, NewVec3(0, 1, 0), goal, NewPathOptions(testPlayerAABB))
+	defer p.Release()
+
+	if p.Partial || p.Nodes[len(p.Nodes)-1] != goal {
+		t.Fatalf("path should step up to %v, got %v", goal, p.Nodes)
+	}
+
+	// Two blocks is too high
+	fillTestBlocks(d, NewVec3(3, 2, -8), NewVec3(24, 2, 8), testStone)
+	p2 := FindPath(d, NewVec3(0, 1, 0), NewVec3(6, 3, 0), NewPathOptions(testPlayerAABB))
+	defer p2.Release()
+	if !p2.Partial {
+		t.Fatalf("path shouldn't climb two blocks, got %v", p2.Nodes)
+	}
+}
+
+func TestFindPathNodeBudget(t *testing.T) {
+	d := newTestFloor()
+	opts := NewPathOptions(testPlayerAABB)
+	opts.MaxNodes = 5
+
+	start, goal := NewVec3(0, 1, 0), NewVec3(20, 1, 0)
+	p := FindPath(d, start, goal, opts)
+	defer p.Release()
+
+	if !p.Partial {
+		t.Fatal("path should be partial when out of nodes")
+	}
+	end := p.Nodes[len(p.Nodes)-1]
+	if pathHeuristic(end, goal) >= pathHeuristic(start, goal) {
+		t.Fatalf("partial path should get closer to the goal, ended at %v", end)
+	}
+}
+
+func TestFindPathUnreachable(t *testing.T) {
+	d := newTestFloor()
+	// Wall the goal in
+	fillTestBlocks(d, NewVec3(8, 1, -2), NewVec3(12, 3, 2), testStone)
+	fillTestBlocks(d, NewVec3(9, 1, -1), NewVec3(11, 3, 1), nil)
+
+	start, goal := NewVec3(0, 1, 0), NewVec3(10, 1, 0)
+	p := FindPath(d, start, goal, NewPathOptions(testPlayerAABB))
+	defer p.Release()
+
+	if !p.Partial {
+		t.Fatalf("goal shouldn't be reachable, got %v", p.Nodes)
+	}
+	for _, v := range p.Nodes {
+		if v == goal {
+			t.Fatalf("path shouldn't reach the goal, got %v", p.Nodes)
+		}
+	}
+}
+
+func TestPathInvalidation(t *testing.T) {
+	d := newTestFloor()
+	p := FindPath(d, NewVec3(0, 1, 0), NewVec3(5, 1, 0), NewPathOptions(testPlayerAABB))
+
+	// Blocks away from the path don't matter
+	d.SetBlockAt(Block{Position: NewVec3(0, 1, 6), Type: testStone})
+	if !p.Valid() {
+		t.Fatal("path shouldn't be invalidated by a block off it")
+	}
+
+	d.SetBlockAt(Block{Position: NewVec3(3, 1, 0), Type: testStone})
+	if p.Valid() {
+		t.Fatal("path should be invalidated by a block on it")
+	}
+	if len(d.paths) != 0 {
+		t.Fatalf("invalidated path should be released, %d blocks still watched", len(d.paths))
+	}
+}
@@ -4,6 +4,12 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// The number of game ticks per second, on both the client and the server
+const TICK_RATE = 20
+
+// How high players and most mobs can walk up without jumping, in blocks
+const DEFAULT_STEP_HEIGHT = 0.6
+
 type PhysicsFace interface {
 	GetPhysicsComp() *PhysicsComp
 }
@@ -11,9 +17,24 @@ type PhysicsFace interface {
 type PhysicsComp struct {
 	AABB      mgl32.Vec3 // AABB cannot be < 0
 	NoGravity bool
+	NoClip    bool       // pass through blocks without colliding
 	Velocity  mgl32.Vec3 // in m/s
 
+	// How high the body can walk up without jumping, such as onto slabs
+	StepHeight float32
+	// Set to stop the body walking off edges, such as when players sneak
+	StayOnEdges bool
+	// Set for bodies which swim up in fluids, such as mobs
+	Floats bool
+
 	onGround bool
+	// The type of the block the body is standing on, or was last
+	ground   *BlockType
+	climbing bool
+	fluid    *FluidType
+
+	// The fastest speed the entity has landed at since TakeLandingSpeed, in m/s
+	landingSpeed float32
 }
 
 func (p *PhysicsComp) GetPhysicsComp() *PhysicsComp {
@@ -24,158 +45,315 @@ func (p *PhysicsComp) OnGround() bool {
 	return p.onGround
 }
 
-func PhysicsTickSystem(dim *Dimension) {
-	bodies := GetEntitiesSatisfying[interface {
-		PhysicsFace
-		PositionFace
-	}](dim.Entities)
+// Returns the fastest speed the entity has landed at since the last call, such
+// as to calculate fall damage
+func (p *PhysicsComp) TakeLandingSpeed() float32 {
+	s := p.landingSpeed
+	p.landingSpeed = 0
+	return s
+}
+
+// An entity which is moved by the physics
+type Body interface {
+	Entity
+	PhysicsFace
+	PositionFace
+}
 
-	for _, v := range bodies {
-		e := v.GetPhysicsComp()
+// Runs a tick of physics for every body. Physics only happens in whole ticks,
+// so the client and server move bodies the same however fast they run, and
+// clients interpolate between ticks when rendering.
+func PhysicsSystem(dim *Dimension) {
+	for _, v := range Query[Body](&dim.Entities) {
+		StepBody(dim, v)
+	}
+}
 
-		if !e.NoGravity {
-			// Vertical speed decremented (less upward motion, more downward motion)
-			// by 0.08 blocks per tick (32 m/s), then multiplied by 0.98 per tick.
-			e.Velocity = e.Velocity.Add(GRAVITY.Mul(1.0 / 20))
-			e.Velocity[1] *= 0.98
-		}
+// Runs a tick of physics for the body, applying gravity, or the fluid it's
+// in, and then moving it.
+// This is the only way bodies should be moved, including when replaying the
+// local player's movement.
+func StepBody(dim *Dimension, v Body) {
+	e := v.GetPhysicsComp()
+	e.fluid = nil
+	if !e.NoClip {
+		e.fluid = fluidAt(dim, *v.GetPosition(), e.AABB)
 	}
+
+	if e.fluid != nil {
+		applyFluid(e, e.fluid)
+	} else {
+		applyGravity(e)
+	}
+	moveBody(dim, v, 1.0/TICK_RATE)
 }
 
-func PhysicsSystem(dim *Dimension, deltaT float32) {
-	bodies := GetEntitiesSatisfying[interface {
-		PhysicsFace
-		PositionFace
-	}](dim.Entities)
+// Accelerates the body downwards for a tick, unless it has NoGravity
+func applyGravity(e *PhysicsComp) {
+	if !e.NoGravity {
+		// Vertical speed decremented (less upward motion, more downward motion)
+		// by 0.08 blocks per tick (32 m/s), then multiplied by 0.98 per tick.
+		e.Velocity = e.Velocity.Add(GRAVITY.Mul(1.0 / TICK_RATE))
+		e.Velocity[1] *= 0.98
+	}
+}
 
-	for _, v := range bodies {
-		e := v.GetPhysicsComp()
-		pos := v.GetPosition()
+// Moves the body by its velocity, stopping it at any blocks in the way
+func moveBody(dim *Dimension, v Body, deltaT float32) {
+	e := v.GetPhysicsComp()
+	pos := v.GetPosition()
 
-		// Update the entity's velocity based on the minecraft movement formula
-		// https://www.mcpk.wiki/wiki/Vertical_Movement_Formulas
-		// Note that we do things in m/s, not m/tick
+	// Update the entity's velocity based on the minecraft movement formula
+	// https://www.mcpk.wiki/wiki/Vertical_Movement_Formulas
+	// Note that we do things in m/s, not m/tick
+	applyClimbing(dim, e, *pos)
+	move := e.Velocity.Mul(deltaT)
 
-		// Move the player according to the current velocity
-		*pos = pos.Add(e.Velocity.Mul(deltaT))
+	if e.NoClip {
+		*pos = pos.Add(move)
+		e.onGround = false
+		dim.Entities.Moved(v)
+		return
+	}
 
-		// Continually resolve collision, up to a maxiumum of 16 per update
-		collisionsPerUpdate := 16
-		var yAxisResolved bool
-		for {
-			intersectingBlock, intersects := getBlockIntersecting(dim, *pos, e.AABB)
-			if !intersects {
-				break
+	// Don't walk off edges
+	if e.StayOnEdges && e.onGround {
+		clamped := clampToEdges(dim, *pos, e.AABB, move, e.StepHeight)
+		for i := 0; i < 3; i++ {
+			if clamped[i] != move[i] {
+				e.Velocity[i] = 0
 			}
+		}
+		move = clamped
+	}
 
-			// Calculate penetration time of the entity in the block
-			// (How long ago did the entity start penetrating this block)
-			var penTime mgl32.Vec3
-			bl := intersectingBlock.ToFloat()
+	// Move the entity according to the current velocity
+	newPos, blocked := collide(dim, *pos, e.AABB, move)
 
-			// X Axis
-			if e.Velocity.X() != 0 {
-				// Compute the smallest intersection interval in terms of time
-				d0 := bl.X() + 1 - pos.X()
-				d1 := pos.X() + e.AABB.X() - bl.X()
+	// Step up onto anything low enough which stopped the entity, if it gets
+	// the entity further
+	if (blocked[0] || blocked[2]) && e.onGround && e.StepHeight > 0 && move.Y() <= 0 {
+		stepPos, stepBlocked, ok := stepUp(dim, *pos, e.AABB, move, e.StepHeight)
+		if ok && horizontalDistanceSq(*pos, stepPos) > horizontalDistanceSq(*pos, newPos) {
+			newPos, blocked = stepPos, stepBlocked
+		}
+	}
+	*pos = newPos
 
-				if d0 > 0 && d1 > 0 {
-					if d0 < d1 {
-						penTime[0] = d0 / e.Velocity.X()
-					} else {
-						penTime[0] = -d1 / e.Velocity.X()
-					}
-				}
-			}
-			if penTime[0] >= 0 {
-				penTime[0] = mgl32.InfNeg
-			}
+	if blocked[0] {
+		e.Velocity[0] = 0
+	}
+	if blocked[2] {
+		e.Velocity[2] = 0
+	}
+	var landing float32
+	if blocked[1] {
+		if move.Y() < 0 && !e.onGround {
+			landing = -e.Velocity.Y()
+		}
+		e.Velocity[1] = 0
+	}
 
-			// Y Axis
-			if e.Velocity.Y() != 0 {
-				// Compute the smallest intersection interval
-				d0 := bl.Y() + 1 - pos.Y()
-				d1 := pos.Y() + e.AABB.Y() - bl.Y()
+	if blocked[1] && move.Y() < 0 {
+		e.onGround = true
+	} else if move.Y() != 0 {
+		e.onGround = false
+	}
 
-				if d0 > 0 && d1 > 0 {
-					if d0 < d1 {
-						penTime[1] = d0 / e.Velocity.Y()
-					} else {
-						penTime[1] = -d1 / e.Velocity.Y()
-					}
-				}
-			}
-			if penTime[1] >= 0 {
-				penTime[1] = mgl32.InfNeg
-			}
+	applySurface(dim, e, *pos, landing, blocked)
 
-			// Z Axis
-			if e.Velocity.Z() != 0 {
+	// Bouncing doesn't hurt
+	if e.onGround && landing > e.landingSpeed {
+		e.landingSpeed = landing
+	}
+
+	dim.Entities.Moved(v)
+}
+
+// Moves the box at the position by the move, stopping it at any blocks in the
+// way. Returns where it ends up, and which axes it was stopped on.
+func collide(dim *Dimension, pos, aabb, move mgl32.Vec3) (mgl32.Vec3, [3]bool) {
+	var blocked [3]bool
+	pos = pos.Add(move)
+
+	// Continually resolve collision, up to a maxiumum of 16 per update
+	collisionsPerUpdate := 16
+	for {
+		boxMin, boxMax, intersects := getBoxIntersecting(dim, pos, aabb)
+		if !intersects {
+			break
+		}
+
+		// Calculate penetration time of the box in the block's box, as a
+		// fraction of the move
+		// (How long ago did the box start penetrating the block's box)
+		var penTime mgl32.Vec3
+		for i := 0; i < 3; i++ {
+			if move[i] != 0 {
 				// Compute the smallest intersection interval
-				d0 := bl.Z() + 1 - pos.Z()
-				d1 := pos.Z() + e.AABB.Z() - bl.Z()
+				d0 := boxMax[i] - pos[i]
+				d1 := pos[i] + aabb[i] - boxMin[i]
 
 				if d0 > 0 && d1 > 0 {
 					if d0 < d1 {
-						penTime[2] = d0 / e.Velocity.Z()
+						penTime[i] = d0 / move[i]
 					} else {
-						penTime[2] = -d1 / e.Velocity.Z()
+						penTime[i] = -d1 / move[i]
 					}
 				}
 			}
-			if penTime[2] >= 0 {
-				penTime[2] = mgl32.InfNeg
+			if penTime[i] >= 0 {
+				penTime[i] = mgl32.InfNeg
 			}
+		}
 
-			// Resolve the penetration by translating the entity to the latest time
-			// the intersection could have happened
-			if penTime.X() >= penTime.Y() && penTime.X() >= penTime.Z() {
-				pos[0] += penTime.X() * e.Velocity.X()
-				e.Velocity[0] = 0
-			} else if penTime.Z() >= penTime.X() && penTime.Z() >= penTime.Y() {
-				pos[2] += penTime.Z() * e.Velocity.Z()
-				e.Velocity[2] = 0
-			} else if penTime.Y() >= penTime.X() && penTime.Y() >= penTime.Z() {
-				pos[1] += penTime.Y() * e.Velocity.Y()
-				e.Velocity[1] = 0
-				yAxisResolved = true
-			}
+		// The box can't have moved into the block's box, such as when it
+		// was already inside it
+		if penTime.X() == mgl32.InfNeg && penTime.Y() == mgl32.InfNeg && penTime.Z() == mgl32.InfNeg {
+			break
+		}
 
-			collisionsPerUpdate--
-			if collisionsPerUpdate == 0 {
-				break
-			}
+		// Resolve the penetration by translating the box to the latest time
+		// the intersection could have happened. The move isn't changed, as
+		// the box may need pushing back along the same axis again, such as
+		// out of both a block and a slab on top of it.
+		if penTime.X() >= penTime.Y() && penTime.X() >= penTime.Z() {
+			pos[0] += penTime.X() * move.X()
+			blocked[0] = true
+		} else if penTime.Z() >= penTime.X() && penTime.Z() >= penTime.Y() {
+			pos[2] += penTime.Z() * move.Z()
+			blocked[2] = true
+		} else if penTime.Y() >= penTime.X() && penTime.Y() >= penTime.Z() {
+			pos[1] += penTime.Y() * move.Y()
+			blocked[1] = true
 		}
 
-		if yAxisResolved {
-			e.onGround = true
-		} else if e.Velocity.Y() != 0 {
-			e.onGround = false
+		collisionsPerUpdate--
+		if collisionsPerUpdate == 0 {
+			break
 		}
 	}
+
+	return pos, blocked
+}
+
+// Tries moving the box by the move after lifting it by up to the step height,
+// then puts it back down. Returns false if there isn't room to step up.
+func stepUp(dim *Dimension, pos, aabb, move mgl32.Vec3, height float32) (mgl32.Vec3, [3]bool, bool) {
+	// Lift
+	up, _ := collide(dim, pos, aabb, mgl32.Vec3{0, height, 0})
+	lifted := up.Y() - pos.Y()
+	if lifted <= 0 {
+		return pos, [3]bool{}, false
+	}
+
+	// Move across
+	across, blocked := collide(dim, up, aabb, mgl32.Vec3{move.X(), 0, move.Z()})
+
+	// Put back down, as far as it was lifted and then as far as it was
+	// moving down
+	down, downBlocked := collide(dim, across, aabb, mgl32.Vec3{0, move.Y() - lifted, 0})
+	blocked[1] = downBlocked[1]
+	return down, blocked, true
 }
 
-// Gets the first block that with the entity's AABB
-func getBlockIntersecting(dim *Dimension, pos mgl32.Vec3, aabb mgl32.Vec3) (block Vec3, intersects bool) {
+// The square of the horizontal distance between the positions
+func horizontalDistanceSq(a, b mgl32.Vec3) float32 {
+	d := b.Sub(a)
+	return d.X()*d.X() + d.Z()*d.Z()
+}
+
+// The distance moved along each axis, at a time, when looking for how far a
+// body can move before it would walk off an edge
+const EDGE_CLAMP_STEP = 0.05
+
+// Whether the box at the position has something to stand on within the depth
+// below it
+func HasGroundBelow(dim *Dimension, pos, aabb mgl32.Vec3, depth float32) bool {
+	if depth < COLLISION_EPSILON {
+		depth = COLLISION_EPSILON
+	}
+	_, _, intersects := getBoxIntersecting(dim, pos.Sub(mgl32.Vec3{0, depth, 0}), mgl32.Vec3{aabb.X(), depth, aabb.Z()})
+	return intersects
+}
+
+// Shortens the horizontal part of the move so the box doesn't move off an edge
+// with nothing to stand on within the depth below it, like sneaking players.
+// The same as Minecraft, so players can still sneak to the very edge.
+func clampToEdges(dim *Dimension, pos, aabb, move mgl32.Vec3, depth float32) mgl32.Vec3 {
+	shrink := func(d float32) float32 {
+		switch {
+		case d < EDGE_CLAMP_STEP && d >= -EDGE_CLAMP_STEP:
+			return 0
+		case d > 0:
+			return d - EDGE_CLAMP_STEP
+		default:
+			return d + EDGE_CLAMP_STEP
+		}
+	}
+	ground := func(dx, dz float32) bool {
+		return HasGroundBelow(dim, pos.Add(mgl32.Vec3{dx, 0, dz}), aabb, depth)
+	}
+
+	x, z := move.X(), move.Z()
+	for x != 0 && !ground(x, 0) {
+		x = shrink(x)
+	}
+	for z != 0 && !ground(0, z) {
+		z = shrink(z)
+	}
+	for x != 0 && z != 0 && !ground(x, z) {
+		x, z = shrink(x), shrink(z)
+	}
+
+	return mgl32.Vec3{x, move.Y(), z}
+}
+
+// Gets the first collision box of a block that intersects with the entity's
+// AABB, in world space
+func getBoxIntersecting(dim *Dimension, pos mgl32.Vec3, aabb mgl32.Vec3) (min, max mgl32.Vec3, intersects bool) {
 	// Iterate over each block that could intersect the AABB
-	// and get the first one that intersects
+	// and get the first one that intersects. Boxes can stick out of the top
+	// of blocks, so blocks below the AABB are checked too.
 	minX := FloorFloat32(pos.X())
-	minY := FloorFloat32(pos.Y())
+	minY := FloorFloat32(pos.Y()) - MAX_COLLISION_BOX_OVERHANG
 	minZ := FloorFloat32(pos.Z())
 
 	maxX := CeilFloat32(pos.X() + aabb.X())
 	maxY := CeilFloat32(pos.Y() + aabb.Y())
 	maxZ := CeilFloat32(pos.Z() + aabb.Z())
 
+	end := pos.Add(aabb)
 	for x := minX; x < maxX; x++ {
 		for y := minY; y < maxY; y++ {
 			for z := minZ; z < maxZ; z++ {
-				if dim.GetBlockAt(NewVec3(x, y, z)).Type != nil {
-					return NewVec3(x, y, z), true
+				b := dim.GetBlockAt(NewVec3(x, y, z))
+				if b.Type == nil {
+					continue
+				}
+
+				for _, v := range b.Type.GetCollisionBoxes() {
+					bmin, bmax := v.At(b.Position)
+					if pos.X() < bmax.X() && end.X() > bmin.X() &&
+						pos.Y() < bmax.Y() && end.Y() > bmin.Y() &&
+						pos.Z() < bmax.Z() && end.Z() > bmin.Z() {
+						return bmin, bmax, true
+					}
 				}
 			}
 		}
 	}
 
-	return Vec3{}, false
+	return mgl32.Vec3{}, mgl32.Vec3{}, false
+}
+
+// Overlaps with blocks smaller than this are ignored by AABBIntersectsBlocks,
+// as bodies stopped by blocks can overlap them slightly from rounding
+const COLLISION_EPSILON = 0.001
+
+// Whether the box at the position overlaps any blocks
+func AABBIntersectsBlocks(dim *Dimension, pos, aabb mgl32.Vec3) bool {
+	eps := mgl32.Vec3{COLLISION_EPSILON, COLLISION_EPSILON, COLLISION_EPSILON}
+	_, _, intersects := getBoxIntersecting(dim, pos.Add(eps), aabb.Sub(eps.Mul(2)))
+	return intersects
 }
@@ -0,0 +1,236 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// A player moved by inputs, the same as on the client and server
+type testPlayer struct {
+	testBody
+	MovementComp
+	HungerComp
+}
+
+func newTestPlayer(pos mgl32.Vec3) *testPlayer {
+	p := &testPlayer{
+		testBody:     *newTestBody(pos, PLAYER_AABB),
+		MovementComp: NewMovementComp(),
+		HungerComp:   NewHungerComp(),
+	}
+	p.StepHeight = DEFAULT_STEP_HEIGHT
+	return p
+}
+
+// Runs the inputs, returning where the player is after each tick
+func replayInputs(d *Dimension, p PlayerBody, inputs []PlayerInput) []mgl32.Vec3 {
+	var out []mgl32.Vec3
+	for _, v := range inputs {
+		ApplyPlayerInput(p, v)
+		StepBody(d, p)
+		out = append(out, *p.GetPosition())
+	}
+	return out
+}
+
+// Repeats the input for the number of ticks
+func holdInput(in PlayerInput, ticks int) []PlayerInput {
+	out := make([]PlayerInput, ticks)
+	for i := range out {
+		out[i] = in
+	}
+	return out
+}
+
+// Terrain with a step up onto slabs, and a wall too high to step up
+func newTestCourse() *Dimension {
+	d := newTestFloor()
+	fillTestBlocks(d, NewVec3(4, 1, -8), NewVec3(24, 1, 8), testSlab)
+	fillTestBlocks(d, NewVec3(10, 1, -8), NewVec3(24, 2, 8), testStone)
+	return d
+}
+
+// Landing, walking, sprinting, stepping onto a slab, jumping, strafing, and
+// walking into a wall, facing east along +X
+func recordedInputs() []PlayerInput {
+	east := math.Pi / 2
+	var inputs []PlayerInput
+	inputs = append(inputs, holdInput(PlayerInput{Idle: true}, 4)...)
+	inputs = append(inputs, holdInput(PlayerInput{Forward: true, Azimuth: east}, 12)...)
+	inputs = append(inputs, holdInput(PlayerInput{Forward: true, Sprint: true, Azimuth: east}, 6)...)
+	inputs = append(inputs, holdInput(PlayerInput{Forward: true, Sprint: true, Jump: true, Azimuth: east}, 2)...)
+	inputs = append(inputs, holdInput(PlayerInput{Forward: true, Left: true, Azimuth: east}, 10)...)
+	inputs = append(inputs, holdInput(PlayerInput{Forward: true, Azimuth: east}, 12)...)
+	inputs = append(inputs, holdInput(PlayerInput{}, 4)...)
+	return inputs
+}
+
+var testPlayerStart = mgl32.Vec3{0.2, 1.5, -0.3}
+
+// Where the player is after each tick of recordedInputs. Only update these
+// when the movement is meant to change.
+var goldenPositions = []mgl32.Vec3{
+	{0.2, 1.4216, -0.3},
+	{0.2, 1.2663679, -0.3},
+	{0.2, 1.0358405, -0.3},
+	{0.2, 1, -0.3}, // landed
+	{0.29799998, 1, -0.3},
+	{0.44950798, 1, -0.3},
+	{0.6302314, 1, -0.3},
+	{0.8269063, 1, -0.3},
+	{1.0322908, 1, -0.3},
+	{1.2424308, 1, -0.3},
+	{1.4551672, 1, -0.3},
+	{1.6693213, 1, -0.3},
+	{1.8842494, 1, -0.3},
+	{2.0996003, 1, -0.3},
+	{2.3151817, 1, -0.3},
+	{2.5308893, 1, -0.3},
+	{2.7760656, 1, -0.3},
+	{3.0373318, 1, -0.3},
+	{3.3073833, 1, -0.3},
+	{3.5822313, 1.5, -0.3}, // stepped onto the slab
+	{3.8596983, 1.5, -0.3},
+	{4.138595, 1.5, -0.3},
+	{4.527473, 1.8332, -0.3}, // jumped
+	{4.9068317, 2.081336, -0.3},
+	{5.270433, 2.2461092, -0.3183848},
+	{5.6196947, 2.3291872, -0.3534997},
+	{5.955908, 2.3322034, -0.40383908},
+	{6.2802467, 2.2567594, -0.4680327},
+	{6.5937796, 2.1044242, -0.54483366},
+	{6.8974795, 1.8767358, -0.6331073},
+	{7.192231, 1.5752012, -0.7318211},
+	{7.47884, 1.5, -0.84003544},
+	{7.727252, 1.5, -0.99104434},
+	{7.954809, 1.5, -1.1654191},
+	{8.206455, 1.5, -1.2606277},
+	{8.471254, 1.5, -1.3126117},
+	{8.743235, 1.5, -1.340995},
+	{9.019135, 1.5, -1.3564922},
+	{9.297177, 1.5, -1.3649536},
+	{9.4, 1.5, -1.3695736}, // stopped by the wall
+	{9.4, 1.5, -1.3720961},
+	{9.4, 1.5, -1.3734734},
+	{9.4, 1.5, -1.3742254},
+	{9.4, 1.5, -1.3746359},
+	{9.4, 1.5, -1.3748602},
+	{9.4, 1.5, -1.3749826},
+	{9.4, 1.5, -1.3750495},
+	{9.4, 1.5, -1.375086},
+	{9.4, 1.5, -1.3751059},
+	{9.4, 1.5, -1.3751167},
+}
+
+func TestGoldenTrajectory(t *testing.T) {
+	got := replayInputs(newTestCourse(), newTestPlayer(testPlayerStart), recordedInputs())
+	if len(got) != len(goldenPositions) {
+		t.Fatalf("should have %d positions, got %d", len(goldenPositions), len(got))
+	}
+	for i, v := range got {
+		if !v.ApproxEqualThreshold(goldenPositions[i], 1e-5) {
+			t.Fatalf("position after tick %d should be %v, got %v", i, goldenPositions[i], v)
+		}
+	}
+}
+
+// The client replays inputs from a tick the server corrected, which must end
+// up in exactly the same place as running them the first time
+func TestReplayMatches(t *testing.T) {
+	inputs := recordedInputs()
+	d := newTestCourse()
+	p := newTestPlayer(testPlayerStart)
+
+	const from = 20
+	replayInputs(d, p, inputs[:from])
+	saved := *p
+	want := replayInputs(d, p, inputs[from:])
+
+	*p = saved
+	got := replayInputs(d, p, inputs[from:])
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("replayed position after tick %d should be %v, got %v", from+i, want[i], got[i])
+		}
+	}
+}
+
+// Lands the player, then walks them east for the ticks, returning where they
+// end up
+func walkEast(d *Dimension, p *testPlayer, sneak bool, ticks int) mgl32.Vec3 {
+	// Land first
+	replayInputs(d, p, holdInput(PlayerInput{Idle: true}, 2))
+	in := PlayerInput{Forward: true, Sneak: sneak, Azimuth: math.Pi / 2}
+	positions := replayInputs(d, p, holdInput(in, ticks))
+	return positions[len(positions)-1]
+}
+
+func TestStepUp(t *testing.T) {
+	tests := []struct {
+		name   string
+		block  *BlockType
+		height float32
+		// Where the player should end up after walking into the block
+		x, y float32
+	}{
+		{"onto a slab", testSlab, DEFAULT_STEP_HEIGHT, 4, 1.5},
+		{"not onto a full block", testStone, DEFAULT_STEP_HEIGHT, 2 - PLAYER_AABB.X(), 1},
+		{"not without a step height", testSlab, 0, 2 - PLAYER_AABB.X(), 1},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			d := newTestFloor()
+			fillTestBlocks(d, NewVec3(2, 1, -8), NewVec3(24, 1, 8), v.block)
+
+			p := newTestPlayer(mgl32.Vec3{0.2, 1, 0.2})
+			p.StepHeight = v.height
+			pos := walkEast(d, p, false, 20)
+
+			if pos.Y() != v.y {
+				t.Errorf("player should be at y = %v, got %v", v.y, pos)
+			}
+			if v.y == 1 && math.Abs(float64(pos.X()-v.x)) > 1e-4 {
+				t.Errorf("player should be stopped at x = %v, got %v", v.x, pos)
+			}
+			if v.y != 1 && pos.X() < v.x {
+				t.Errorf("player should walk on past x = %v, got %v", v.x, pos)
+			}
+		})
+	}
+}
+
+func TestSneakToEdge(t *testing.T) {
+	d := newTestFloor()
+	// The floor ends at x = 25
+	const edge = 25
+
+	p := newTestPlayer(mgl32.Vec3{22, 1, 0.2})
+	pos := walkEast(d, p, true, 100)
+
+	if pos.Y() != 1 {
+		t.Fatalf("sneaking player shouldn't fall off the edge, got %v", pos)
+	}
+	// Right up to the edge, with only the back of the player over the block
+	if pos.X() >= edge || pos.X() < edge-EDGE_CLAMP_STEP {
+		t.Fatalf("sneaking player should stop at the very edge, got %v", pos)
+	}
+
+	// Walking on falls off
+	pos = walkEast(d, p, false, 20)
+	if pos.Y() >= 1 {
+		t.Fatalf("walking player should fall off the edge, got %v", pos)
+	}
+}
+
+func TestSneakDownStep(t *testing.T) {
+	d := newTestFloor()
+	fillTestBlocks(d, NewVec3(-8, 1, -8), NewVec3(4, 1, 8), testSlab)
+
+	// Drops within the step height aren't edges
+	p := newTestPlayer(mgl32.Vec3{3, 1.5, 0.2})
+	if pos := walkEast(d, p, true, 40); pos.Y() != 1 || pos.X() < 5 {
+		t.Fatalf("sneaking player should walk down off the slab, got %v", pos)
+	}
+}
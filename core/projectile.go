@@ -0,0 +1,171 @@
+package core
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// An entity which flies through the air until it hits something, such as an arrow
+type Projectile interface {
+	Entity
+	PositionFace
+	PhysicsFace
+	LookFace
+	ProjectileFace
+}
+
+type ProjectileFace interface {
+	GetProjectileComp() *ProjectileComp
+}
+
+// Projectiles move themselves, so their PhysicsComp should have NoGravity and
+// NoClip set, and only be used for the velocity and AABB.
+type ProjectileComp struct {
+	// The entity which threw or shot the projectile, which it can't hit
+	Owner uuid.UUID
+	// The damage done to entities which are hit
+	Damage float32
+	// The downwards acceleration, in m/s^2
+	Gravity float32
+	// The fraction of the velocity kept each tick
+	Drag float32
+
+	// Whether the projectile stays in blocks it hits, or else is removed
+	Sticks bool
+	// The ticks the projectile lasts for once stuck in a block
+	StuckLifetime int
+
+	Stuck   bool
+	StuckIn Vec3
+	// The ticks since the projectile got stuck
+	StuckTime int
+}
+
+func (p *ProjectileComp) GetProjectileComp() *ProjectileComp {
+	return p
+}
+
+// Something a projectile can hit, such as a player or a mob
+type ProjectileTarget struct {
+	ID       uuid.UUID
+	Position mgl32.Vec3
+	AABB     mgl32.Vec3
+}
+
+type ProjectileContext struct {
+	Dim     *Dimension
+	Targets []ProjectileTarget
+
+	// Called when a projectile hits a target, before it is removed
+	Hit func(p Projectile, target uuid.UUID)
+	// Called when a projectile should be removed from the dimension
+	Remove func(p Projectile)
+	// Returns where the owner of the projectile saw the target, for lag
+	// compensation. Optional.
+	Rewind func(p Projectile, target ProjectileTarget) ProjectileTarget
+}
+
+// Applies gravity and drag to all projectiles, and checks what they will hit
+// this tick. The whole path of the projectile is checked, so that fast
+// projectiles can't pass through things.
+// Should be run every tick, before the PhysicsSystem, which moves the
+// projectiles that haven't hit anything.
+func ProjectileSystem(ctx *ProjectileContext, deltaT float32) {
+	Each(&ctx.Dim.Entities, func(p Projectile) {
+		pc := p.GetProjectileComp()
+		phys := p.GetPhysicsComp()
+		pos := p.GetPosition()
+
+		if pc.Stuck {
+			if ctx.Dim.GetBlockAt(pc.StuckIn).IsSolid() {
+				pc.StuckTime++
+				if pc.StuckTime >= pc.StuckLifetime {
+					ctx.Remove(p)
+				}
+				return
+			}
+
+			// The block was removed, so fall
+			pc.Stuck = false
+			pc.StuckTime = 0
+		}
+
+		phys.Velocity[1] -= pc.Gravity * deltaT
+		phys.Velocity = phys.Velocity.Mul(pc.Drag)
+
+		move := phys.Velocity.Mul(deltaT)
+		dist := move.Len()
+		if dist == 0 {
+			return
+		}
+		dir := move.Normalize()
+		half := phys.AABB.Mul(0.5)
+		centre := pos.Add(half)
+
+		// Face the direction of travel
+		look := p.GetLookComp()
+		look.Azimuth, look.Elevation = DirectionToLook(dir)
+
+		// Find the first block in the way
+		blockT := dist
+		var block mgl32.Vec3
+		var hitBlock bool
+		TraceRay(dir, centre, dist, func(b, _ mgl32.Vec3) (stop bool) {
+			bl := ctx.Dim.GetBlockAt(NewVec3FromFloat(b))
+			if !bl.IsSolid() {
+				return false
+			}
+
+			t, ok := RayIntersectsBoxes(centre, dir, bl.Position, bl.Type.GetCollisionBoxes())
+			if !ok || t > dist {
+				return false
+			}
+
+			blockT = t
+			block = b
+			hitBlock = true
+			return true
+		})
+
+		// Find the first target in the way before the block. The boxes of the
+		// targets are grown by the size of the projectile, so that the centre
+		// of the projectile can be traced as a ray.
+		var target *ProjectileTarget
+		targetT := blockT
+		for k, v := range ctx.Targets {
+			if v.ID == pc.Owner {
+				continue
+			}
+			if ctx.Rewind != nil {
+				v = ctx.Rewind(p, v)
+			}
+
+			min := v.Position.Sub(half)
+			t, ok := RayIntersectsAABB(centre, dir, min, v.Position.Add(v.AABB).Add(half))
+			if ok && t <= targetT {
+				target, targetT = &ctx.Targets[k], t
+			}
+		}
+
+		if target != nil {
+			ctx.Hit(p, target.ID)
+			ctx.Remove(p)
+			return
+		}
+
+		if hitBlock {
+			if !pc.Sticks {
+				ctx.Remove(p)
+				return
+			}
+
+			// Stop at the face of the block
+			*pos = centre.Add(dir.Mul(blockT)).Sub(half)
+			ctx.Dim.Entities.Moved(p)
+			phys.Velocity = mgl32.Vec3{}
+			pc.Stuck = true
+			pc.StuckIn = NewVec3FromFloat(block)
+			pc.StuckTime = 0
+		}
+	})
+}
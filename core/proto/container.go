@@ -11,13 +11,6 @@ import (
 // Sent by clients
 type PlayerHeldItem int
 
-// Update the externally visible inventory slots of other entities, such as held items and armor.
-// Sent by the server
-// type EntityEquipment struct {
-// 	core.EntityEquipment
-// 	EntityID uuid.UUID
-// }
-
 // Updates the contents of the currently open screen.
 // May also be sent with by the CONTAINER_OPEN event.
 // Sent by the server
@@ -25,17 +18,80 @@ type ContainerContents struct {
 	EntityID      uuid.UUID
 	Slots         []core.ItemStack
 	FloatingStack core.ItemStack
+
+	// The revision of the player's containers after this update.
+	// See ContainerClick
+	Revision int
 }
 
+// Opens the screen of a container, such as a chest, on the client.
+// The slots are those of the container, followed by the player's inventory.
+// Sent by the server
+type ContainerOpen struct {
+	ContainerContents
+	ContainerType string
+}
+
+// Closes the screen of a container with the entity ID.
+// Sent by clients when they close a screen, and by the server when the
+// container is no longer available (e.g. the chest was broken)
+type ContainerClose uuid.UUID
+
 // Sent when the player clicks on a slot in a container
 // Sent by clients
+//
+// Clients apply clicks to their containers immediately, and increment their
+// revision. The server only accepts a click if the revision it was made at
+// matches its own, otherwise it replies with the correct contents.
 type ContainerClick struct {
 	EntityID  uuid.UUID
 	SlotIndex int
+	Revision  int // the revision before this click
 
 	// Which keys were pressed
 	LeftClick  bool
 	RightClick bool
 	ShiftKey   glfw.Action
-	NumberKey  int
+	NumberKey  int // 1-9 for the hotbar keys, or 0 if none were pressed
+
+	// Whether this left click was the second of a double click
+	DoubleClick bool
+}
+
+// Sent when the player drags the floating stack across several slots,
+// once the mouse button has been released
+// Sent by clients
+type ContainerDrag struct {
+	EntityID    uuid.UUID
+	SlotIndices []int
+	Revision    int // the revision before this drag, see ContainerClick
+
+	// Dragging with the right mouse button places one item in each slot,
+	// otherwise the stack is split evenly
+	RightClick bool
+}
+
+// Applies the click to the container. Used both by the server, and by clients
+// predicting the result.
+func (m ContainerClick) Apply(c core.Container) {
+	if m.SlotIndex < 0 || m.SlotIndex >= len(c.GetSlots()) {
+		return
+	}
+
+	if m.NumberKey >= 1 && m.NumberKey <= 9 {
+		core.SwapWithHotbar(c, m.SlotIndex, m.NumberKey-1)
+	} else if m.ShiftKey == glfw.Press && (m.LeftClick || m.RightClick) {
+		core.QuickMoveSlot(c, m.SlotIndex)
+	} else if m.LeftClick && m.DoubleClick {
+		core.CollectToFloating(c)
+	} else if m.LeftClick {
+		core.LeftClickSlot(c, m.SlotIndex)
+	} else if m.RightClick {
+		core.RightClickSlot(c, m.SlotIndex)
+	}
+}
+
+// Applies the drag to the container. See ContainerClick.Apply
+func (m ContainerDrag) Apply(c core.Container) {
+	core.PaintSlots(c, m.SlotIndices, m.RightClick)
 }
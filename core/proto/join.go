@@ -15,4 +15,6 @@ type Play struct {
 	Player        EntityPosition
 	InitialChunks LoadChunks
 	Inventory     []core.ItemStack
+	GameMode      core.GameMode
+	Health        float32
 }
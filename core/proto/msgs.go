@@ -9,9 +9,6 @@ const (
 	ENTITY_POSITION
 
 	PLAYER_POSITION
-	PLAYER_JUMP
-	PLAYER_SNEAKING
-	PLAYER_SPRINTING
 
 	BLOCK_UPDATE
 	BLOCK_DIG
@@ -21,8 +18,20 @@ const (
 	LOAD_CHUNKS
 
 	PLAYER_HELD_ITEM
-	ENTITY_EQUIPMENT
+	ENTITY_METADATA
 	CONTAINER_CONTENTS
 	CONTAINER_CLICK
 	CONTAINER_OPEN
+	CONTAINER_CLOSE
+	CONTAINER_DRAG
+
+	PLAYER_GAME_MODE
+	PLAYER_HEALTH
+	PLAYER_RESPAWN
+	PLAYER_HUNGER
+	ITEM_USE
+	ENTITY_ATTACK
+	PLAYER_KNOCKBACK
+	PING
+	PONG
 )
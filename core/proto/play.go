@@ -3,6 +3,7 @@ package proto
 import (
 	"bytes"
 	"remakemc/core"
+	"time"
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/google/uuid"
@@ -34,21 +35,61 @@ type BlockUpdate struct {
 	BlockType string
 }
 
-// Indicates the desired state.
+// Changes the player's game mode. The game mode is only ever changed by the server.
+// Sent by the server
+type PlayerGameMode core.GameMode
+
+// The player's new health. The player has died if it is 0.
+// Sent by the server
+type PlayerHealth float32
+
+// PlayerRespawn
+// Requests respawning after death. Has no type. Sent by clients
+//
+// The server replies with where the player has respawned.
+// Sent by the server
+type PlayerRespawn struct {
+	Position mgl32.Vec3
+	Health   float32
+}
+
+// The player's new food and saturation levels.
+// Sent by the server
+type PlayerHunger struct {
+	Food       int
+	Saturation float32
+}
+
+// Message sent when a player starts or finishes using their held item, such as eating.
+// The use button must be held for the item's UseDuration between the two.
 // Sent by clients
-type PlayerSneaking bool
+type ItemUse struct {
+	FinishUsing bool // start using = false
+}
 
-// Indicates the desired state.
+// Attacks the entity with the ID with the held item.
 // Sent by clients
-type PlayerSprinting bool
+type EntityAttack uuid.UUID
 
-// PlayerJump
-// Has no type. Sent by clients
+// Knocks the player away in the direction, such as when attacked.
+// See core.ApplyKnockback. Sent by the server
+type PlayerKnockback struct {
+	Direction mgl32.Vec3
+	Speed     float32
+}
 
 // Updates a player's position and rotations. EntityID will be ignored.
-// Sent by clients
 type PlayerPosition EntityPosition
 
+// A tick of the player's movement. The server runs the input as well, and
+// corrects the client if it ends up somewhere else. Jumping, sneaking and
+// sprinting are also worked out from the input.
+// Sent by clients with every tick
+type PlayerMove struct {
+	PlayerPosition
+	Input core.PlayerInput
+}
+
 // Updates the entity's position absolutely, as well as other movement-related values
 // Sent by the server
 type EntityPosition struct {
@@ -60,15 +101,46 @@ type EntityPosition struct {
 
 	LookAzimuth   float64
 	LookElevation float64
+
+	// The server time of the position, since the server started, which the
+	// client shows entities at. Ignored when sent by clients.
+	Time time.Duration
+
+	// The sequence number of the client's input which led to the position.
+	// Sent by clients with each tick of their movement, and by the server
+	// when it corrects the client's position.
+	Seq int
+	// The velocity of the client's player after the input, in m/s. Only sent
+	// by the server when it corrects the client's position.
+	Velocity mgl32.Vec3 `msgpack:",omitempty"`
 }
 
 type EntityCreate struct {
 	EntityPosition
 	EntityType string
+	// Encoded by the EncodeSpawn of the entity type, if it has one
+	SpawnData []byte `msgpack:",omitempty"`
 }
 
 type EntityDelete uuid.UUID
 
+// Updates what other players can see of an entity, such as its held item and
+// pose, and plays its animations. Only sent to clients with the entity's
+// chunk loaded, and to clients once they load it.
+// Sent by the server
+type EntityMetadata struct {
+	EntityID uuid.UUID
+	Metadata core.EntityMetadata
+	// One-off animations to play, after updating the metadata
+	Animations []core.EntityAnimation `msgpack:",omitempty"`
+}
+
+// Sent by the server with its time, to measure the client's round trip time
+type Ping time.Duration
+
+// Sent by clients with the time of a Ping, as soon as it arrives
+type Pong time.Duration
+
 // Instructs the client to unload the chunk
 // Sent by the server
 type UnloadChunks []core.Vec3
@@ -4,17 +4,23 @@ var BlockRegistry = map[string]*BlockType{
 	"": nil,
 }
 
-var EntityRegistry = map[string]Entity{}
+var EntityRegistry = map[string]*EntityType{}
 
 var ItemRegistry = map[string]*ItemType{}
 
+// By the name of the fluid's source block
+var FluidRegistry = map[string]*FluidType{}
+
+// A slice, so spawning always picks from the rules in the same order
+var SpawnRules []*SpawnRule
+
 func AddBlockToRegistry(b *BlockType) *BlockType {
 	BlockRegistry[b.Name] = b
 	return b
 }
 
-func AddEntityToRegistry(e Entity) Entity {
-	EntityRegistry[e.GetTypeName()] = e
+func AddEntityToRegistry(e *EntityType) *EntityType {
+	EntityRegistry[e.Name] = e
 	return e
 }
 
@@ -22,3 +28,8 @@ func AddItemToRegistry(i *ItemType) *ItemType {
 	ItemRegistry[i.Name] = i
 	return i
 }
+
+func AddSpawnRule(r *SpawnRule) *SpawnRule {
+	SpawnRules = append(SpawnRules, r)
+	return r
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// A box within a block, relative to the block's corner. Boxes usually fit
+// within (0,0,0) to (1,1,1), but collision boxes may stick out, such as fences
+// being taller than a block.
+type BlockBox struct {
+	Min, Max mgl32.Vec3
+}
+
+// Moves the box to the block at the position
+func (b BlockBox) At(pos Vec3) (min, max mgl32.Vec3) {
+	p := pos.ToFloat()
+	return p.Add(b.Min), p.Add(b.Max)
+}
+
+// The shapes of common blocks
+var (
+	FULL_BLOCK_SHAPE = []BlockBox{{Max: mgl32.Vec3{1, 1, 1}}}
+	SLAB_SHAPE       = []BlockBox{{Max: mgl32.Vec3{1, 0.5, 1}}}
+	CARPET_SHAPE     = []BlockBox{{Max: mgl32.Vec3{1, 1.0 / 16, 1}}}
+	// TODO Stairs facing other ways, once blocks have state
+	STAIRS_SHAPE = []BlockBox{
+		{Max: mgl32.Vec3{1, 0.5, 1}},
+		{Min: mgl32.Vec3{0, 0.5, 0.5}, Max: mgl32.Vec3{1, 1, 1}},
+	}
+	// TODO Connect fences to their neighbours
+	FENCE_SHAPE = []BlockBox{
+		{Min: mgl32.Vec3{6.0 / 16, 0, 6.0 / 16}, Max: mgl32.Vec3{10.0 / 16, 1, 10.0 / 16}},
+	}
+	// Fences are taller than they look, so they can't be jumped over
+	FENCE_COLLISION_SHAPE = []BlockBox{
+		{Min: mgl32.Vec3{6.0 / 16, 0, 6.0 / 16}, Max: mgl32.Vec3{10.0 / 16, 1.5, 10.0 / 16}},
+	}
+	PLANT_SHAPE = []BlockBox{
+		{Min: mgl32.Vec3{2.0 / 16, 0, 2.0 / 16}, Max: mgl32.Vec3{14.0 / 16, 13.0 / 16, 14.0 / 16}},
+	}
+)
+
+// How far collision boxes can stick out of the top of their block, so
+// collision checks know how far below a box to look
+const MAX_COLLISION_BOX_OVERHANG = 1
+
+// The boxes which bodies collide with, or none if the block isn't solid
+func (t *BlockType) GetCollisionBoxes() []BlockBox {
+	if t.NoCollision {
+		return nil
+	}
+	if t.CollisionBoxes != nil {
+		return t.CollisionBoxes
+	}
+	return t.GetSelectionBoxes()
+}
+
+// The boxes which are outlined and hit when the player looks at the block
+func (t *BlockType) GetSelectionBoxes() []BlockBox {
+	if t.SelectionBoxes != nil {
+		return t.SelectionBoxes
+	}
+	return FULL_BLOCK_SHAPE
+}
+
+// Whether bodies collide with the block. Empty blocks aren't solid.
+func (b Block) IsSolid() bool {
+	return b.Type != nil && len(b.Type.GetCollisionBoxes()) > 0
+}
+
+// Finds the first of the boxes of the block at the position hit by the ray,
+// returning how far along the ray it is
+func RayIntersectsBoxes(origin, dir mgl32.Vec3, pos Vec3, boxes []BlockBox) (t float32, hit bool) {
+	for _, v := range boxes {
+		min, max := v.At(pos)
+		bt, ok := RayIntersectsAABB(origin, dir, min, max)
+		if ok && (!hit || bt < t) {
+			t, hit = bt, true
+		}
+	}
+	return
+}
+
+// Traces a ray like TraceRay, but only calls back for blocks whose selection
+// boxes are hit by the ray, with where the boxes are hit relative to the
+// block's corner. The ray stops at the first block hit unless the callback
+// says otherwise. Fluids are passed through.
+func TraceBlocks(dim *Dimension, dir mgl32.Vec3, pos mgl32.Vec3, reach float32,
+	callback func(b Block, hit mgl32.Vec3) (stop bool)) {
+	traceBlocks(dim, dir, pos, reach, false, callback)
+}
+
+// Traces a ray like TraceBlocks, but stops at fluids as well, such as to pick
+// them up
+func TraceBlocksAndFluids(dim *Dimension, dir mgl32.Vec3, pos mgl32.Vec3, reach float32,
+	callback func(b Block, hit mgl32.Vec3) (stop bool)) {
+	traceBlocks(dim, dir, pos, reach, true, callback)
+}
+
+func traceBlocks(dim *Dimension, dir mgl32.Vec3, pos mgl32.Vec3, reach float32, fluids bool,
+	callback func(b Block, hit mgl32.Vec3) (stop bool)) {
+	TraceRay(dir, pos, reach, func(v, _ mgl32.Vec3) (stop bool) {
+		b := dim.GetBlockAt(NewVec3FromFloat(v))
+		if b.Type == nil || (b.Type.Fluid != nil && !fluids) {
+			return false
+		}
+
+		t, ok := RayIntersectsBoxes(pos, dir, b.Position, b.Type.GetSelectionBoxes())
+		if !ok || t > reach {
+			return false
+		}
+		return callback(b, pos.Add(dir.Mul(t)).Sub(b.Position.ToFloat()))
+	})
+}
+
+// Returns the face of the block's selection boxes which the hit is on. Falls
+// back to the faces of the whole block, for blocks which are full cubes.
+func FaceFromHit(t *BlockType, hit mgl32.Vec3) (BlockFace, bool) {
+	const eps = 0.001
+
+	if t != nil {
+		for _, v := range t.GetSelectionBoxes() {
+			// Must be on the surface of the box
+			inside := true
+			for i := 0; i < 3; i++ {
+				if hit[i] < v.Min[i]-eps || hit[i] > v.Max[i]+eps {
+					inside = false
+				}
+			}
+			if !inside {
+				continue
+			}
+
+			switch {
+			case mgl32.FloatEqualThreshold(hit.Y(), v.Max.Y(), eps):
+				return FaceTop, true
+			case mgl32.FloatEqualThreshold(hit.Y(), v.Min.Y(), eps):
+				return FaceBottom, true
+			case mgl32.FloatEqualThreshold(hit.X(), v.Min.X(), eps):
+				return FaceLeft, true
+			case mgl32.FloatEqualThreshold(hit.X(), v.Max.X(), eps):
+				return FaceRight, true
+			case mgl32.FloatEqualThreshold(hit.Z(), v.Max.Z(), eps):
+				return FaceFront, true
+			case mgl32.FloatEqualThreshold(hit.Z(), v.Min.Z(), eps):
+				return FaceBack, true
+			}
+		}
+	}
+
+	return faceFromSubvoxel(hit)
+}
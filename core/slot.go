@@ -69,7 +69,7 @@ func (s *InventorySlot) PutStack(i ItemStack) ItemStack {
 		// Put the stack in the empty slot
 		s.Stack = i
 		return ItemStack{}
-	} else if i.Item == s.Stack.Item {
+	} else if i.CanStackWith(s.Stack) {
 		// Merge the stacks, up to max stack size
 		mss := ItemRegistry[i.Item].MaxStackSize
 		if i.Count+s.Stack.Count > mss {
@@ -92,3 +92,37 @@ func (s *InventorySlot) PutStack(i ItemStack) ItemStack {
 func (s *InventorySlot) Temp() bool {
 	return false
 }
+
+// A CreativeSlot provides an unlimited supply of an item.
+// Stacks put into it are destroyed.
+type CreativeSlot struct {
+	Item  string
+	Start mgl32.Vec2
+	End   mgl32.Vec2
+}
+
+func (s *CreativeSlot) GetBox() (start, end mgl32.Vec2) {
+	return s.Start, s.End
+}
+
+func (s *CreativeSlot) GetStack() ItemStack {
+	return ItemStack{Item: s.Item, Count: 1}
+}
+
+func (s *CreativeSlot) SetStack(i ItemStack) {}
+
+// Takes a full stack, or a single item if half is true
+func (s *CreativeSlot) TakeStack(half bool) (ItemStack, bool) {
+	if half {
+		return ItemStack{Item: s.Item, Count: 1}, true
+	}
+	return ItemStack{Item: s.Item, Count: ItemRegistry[s.Item].MaxStackSize}, true
+}
+
+func (s *CreativeSlot) PutStack(i ItemStack) ItemStack {
+	return ItemStack{}
+}
+
+func (s *CreativeSlot) Temp() bool {
+	return true
+}
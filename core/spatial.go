@@ -0,0 +1,193 @@
+package core
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// The size of the cells entities are bucketed into for proximity queries,
+// the same as a chunk
+const SPATIAL_CELL_SIZE = 16
+
+// Entities with a position are kept in buckets by the cell their position is
+// in. As an entity's box can reach into the neighbouring cells, queries also
+// search as far around as the largest box in the store.
+type spatialIndex struct {
+	cells  map[Vec3][]Entity
+	cellOf map[uuid.UUID]Vec3
+	// The biggest size of any box which has been in the store
+	maxSize float32
+}
+
+func spatialCell(pos mgl32.Vec3) Vec3 {
+	v := NewVec3FromFloat(pos)
+	return NewVec3(
+		FlooredDivision(v.X, SPATIAL_CELL_SIZE),
+		FlooredDivision(v.Y, SPATIAL_CELL_SIZE),
+		FlooredDivision(v.Z, SPATIAL_CELL_SIZE),
+	)
+}
+
+// The box of the entity, which is a point if it has no physics
+func entityAABB(e PositionFace) (min, max mgl32.Vec3) {
+	min = *e.GetPosition()
+	if p, ok := e.(PhysicsFace); ok {
+		return min, min.Add(p.GetPhysicsComp().AABB)
+	}
+	return min, min
+}
+
+func (s *spatialIndex) add(e Entity) {
+	p, ok := e.(PositionFace)
+	if !ok {
+		return
+	}
+
+	if s.cells == nil {
+		s.cells = make(map[Vec3][]Entity)
+		s.cellOf = make(map[uuid.UUID]Vec3)
+	}
+
+	min, max := entityAABB(p)
+	for i := 0; i < 3; i++ {
+		if max[i]-min[i] > s.maxSize {
+			s.maxSize = max[i] - min[i]
+		}
+	}
+
+	cell := spatialCell(min)
+	s.cells[cell] = append(s.cells[cell], e)
+	s.cellOf[e.GetID()] = cell
+}
+
+func (s *spatialIndex) remove(id uuid.UUID) {
+	cell, ok := s.cellOf[id]
+	if !ok {
+		return
+	}
+	delete(s.cellOf, id)
+
+	bucket := s.cells[cell]
+	for k, v := range bucket {
+		if v.GetID() == id {
+			bucket = append(bucket[:k], bucket[k+1:]...)
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(s.cells, cell)
+	} else {
+		s.cells[cell] = bucket
+	}
+}
+
+// Moves the entity to the bucket of its current position, if it has changed
+func (s *spatialIndex) moved(e Entity) {
+	p, ok := e.(PositionFace)
+	if !ok {
+		return
+	}
+
+	cell, ok := s.cellOf[e.GetID()]
+	if !ok || cell == spatialCell(*p.GetPosition()) {
+		return
+	}
+
+	s.remove(e.GetID())
+	s.add(e)
+}
+
+// Calls fn for the entities in the cells which could hold entities with boxes
+// overlapping the box, in the same order each time
+func (s *spatialIndex) each(min, max mgl32.Vec3, fn func(e Entity)) {
+	// Entity positions are the corner of their box with the smallest
+	// coordinates, so only boxes starting below the box can reach into it
+	start := spatialCell(min.Sub(mgl32.Vec3{s.maxSize, s.maxSize, s.maxSize}))
+	end := spatialCell(max)
+
+	for x := start.X; x <= end.X; x++ {
+		for y := start.Y; y <= end.Y; y++ {
+			for z := start.Z; z <= end.Z; z++ {
+				for _, v := range s.cells[NewVec3(x, y, z)] {
+					fn(v)
+				}
+			}
+		}
+	}
+}
+
+// Updates where the entity is in the spatial index, after its position has
+// changed. The physics and lerp systems do this for the entities they move.
+func (s *EntityStore) Moved(e Entity) {
+	if s.Get(e.GetID()) == nil {
+		return
+	}
+	s.spatial.moved(e)
+}
+
+// Returns the entities with boxes overlapping the box. Entities without
+// physics are points at their position.
+func (s *EntityStore) InAABB(min, max mgl32.Vec3) (out []Entity) {
+	s.spatial.each(min, max, func(e Entity) {
+		if s.removing[e.GetID()] {
+			return
+		}
+
+		emin, emax := entityAABB(e.(PositionFace))
+		if emin.X() <= max.X() && emax.X() >= min.X() &&
+			emin.Y() <= max.Y() && emax.Y() >= min.Y() &&
+			emin.Z() <= max.Z() && emax.Z() >= min.Z() {
+			out = append(out, e)
+		}
+	})
+	return
+}
+
+// Returns the entities with boxes within the radius of the point
+func (s *EntityStore) InRadius(centre mgl32.Vec3, radius float32) (out []Entity) {
+	r := mgl32.Vec3{radius, radius, radius}
+	s.spatial.each(centre.Sub(r), centre.Add(r), func(e Entity) {
+		if s.removing[e.GetID()] {
+			return
+		}
+
+		emin, emax := entityAABB(e.(PositionFace))
+		if DistanceToAABB(centre, emin, emax) <= radius {
+			out = append(out, e)
+		}
+	})
+	return
+}
+
+// Finds the closest entity with a box hit by the ray within reach, ignoring
+// the entity with the excluded ID. dir must be normalised.
+func (s *EntityStore) Ray(origin, dir mgl32.Vec3, reach float32, exclude uuid.UUID) (hit Entity, t float32) {
+	t = reach
+
+	// Search the box around the whole ray
+	end := origin.Add(dir.Mul(reach))
+	var min, max mgl32.Vec3
+	for i := 0; i < 3; i++ {
+		if origin[i] > end[i] {
+			min[i], max[i] = end[i], origin[i]
+		} else {
+			min[i], max[i] = origin[i], end[i]
+		}
+	}
+
+	s.spatial.each(min, max, func(e Entity) {
+		if e.GetID() == exclude || s.removing[e.GetID()] {
+			return
+		}
+		if _, ok := e.(PhysicsFace); !ok {
+			return
+		}
+
+		emin, emax := entityAABB(e.(PositionFace))
+		et, ok := RayIntersectsAABB(origin, dir, emin, emax)
+		if ok && et <= t {
+			hit, t = e, et
+		}
+	})
+	return
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// Checks whether the store's spatial queries find the entity around the point
+func checkFound(t *testing.T, s *EntityStore, e Entity, point mgl32.Vec3, want bool) {
+	t.Helper()
+
+	contains := func(es []Entity) bool {
+		for _, v := range es {
+			if v == e {
+				return true
+			}
+		}
+		return false
+	}
+
+	r := mgl32.Vec3{0.5, 0.5, 0.5}
+	if got := contains(s.InAABB(point.Sub(r), point.Add(r))); got != want {
+		t.Errorf("InAABB around %v found entity: %v, want %v", point, got, want)
+	}
+	if got := contains(s.InRadius(point, 0.5)); got != want {
+		t.Errorf("InRadius around %v found entity: %v, want %v", point, got, want)
+	}
+
+	// Look down onto the point from above
+	origin := point.Add(mgl32.Vec3{0, 4, 0})
+	hit, _ := s.Ray(origin, mgl32.Vec3{0, -1, 0}, 5, uuid.Nil)
+	if got := hit == e; got != want {
+		t.Errorf("Ray down to %v hit entity: %v, want %v", point, got, want)
+	}
+}
+
+func TestSpatialMoveAcrossCells(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to mgl32.Vec3
+		aabb     mgl32.Vec3
+		// Whether the box at from already covers the corner at to
+		overlaps bool
+	}{
+		{"across a border", mgl32.Vec3{15, 0, 4}, mgl32.Vec3{17, 0, 4}, testPlayerAABB, false},
+		{"into negative cells", mgl32.Vec3{0.2, 0.5, 0.2}, mgl32.Vec3{-0.9, -0.5, -0.9}, testPlayerAABB, false},
+		{"between negative cells", mgl32.Vec3{-15.5, 2, -1}, mgl32.Vec3{-16.5, 2, -33}, testPlayerAABB, false},
+		{"bigger than a cell", mgl32.Vec3{-20, 0, 3}, mgl32.Vec3{10, 0, 3}, mgl32.Vec3{40, 2, 40}, true},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			var s EntityStore
+			e := newTestBody(v.from, v.aabb)
+			s.Add(e)
+
+			// Check the far corner as well, for boxes reaching into other cells
+			far := func(pos mgl32.Vec3) mgl32.Vec3 {
+				return pos.Add(mgl32.Vec3{v.aabb.X() - 0.1, v.aabb.Y() / 2, v.aabb.Z() - 0.1})
+			}
+			near := func(pos mgl32.Vec3) mgl32.Vec3 {
+				return pos.Add(mgl32.Vec3{0.1, v.aabb.Y() / 2, 0.1})
+			}
+
+			checkFound(t, &s, e, near(v.from), true)
+			checkFound(t, &s, e, far(v.from), true)
+			checkFound(t, &s, e, near(v.to), v.overlaps)
+
+			e.Position = v.to
+			s.Moved(e)
+
+			checkFound(t, &s, e, near(v.to), true)
+			checkFound(t, &s, e, far(v.to), true)
+			if !v.overlaps {
+				checkFound(t, &s, e, near(v.from), false)
+			}
+		})
+	}
+}
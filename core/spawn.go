@@ -0,0 +1,225 @@
+package core
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// The brightest a block can be
+const MAX_LIGHT = 15
+
+// The ticks in a day, and when night starts and ends, as in Minecraft
+const (
+	DAY_LENGTH  = 24000
+	NIGHT_START = 13000
+	NIGHT_END   = 23000
+)
+
+// The light of the sky at night, from the moon
+const NIGHT_SKY_LIGHT = 4
+
+// The light level of blocks which can see the sky, which is darker at night.
+// TODO Show night on clients
+func (d *Dimension) SkyLight() int {
+	t := d.Ticks % DAY_LENGTH
+	if t >= NIGHT_START && t < NIGHT_END {
+		return NIGHT_SKY_LIGHT
+	}
+	return MAX_LIGHT
+}
+
+// Whether nothing above the block stops the sky from being seen.
+// Chunks which aren't loaded are treated as empty.
+// You must lock Dim yourself
+func (d *Dimension) CanSeeSky(pos Vec3) bool {
+	for y := pos.Y + 1; ; y++ {
+		above := NewVec3(pos.X, y, pos.Z)
+		if d.GetChunkContaining(above) == nil {
+			// Chunks are generated in whole columns, so nothing is above
+			return true
+		}
+
+		if b := d.GetBlockAt(above); b.Type != nil && !b.Type.Transparent {
+			return false
+		}
+	}
+}
+
+// The light level of the block, from 0 to MAX_LIGHT. Blocks which can see the
+// sky have the SkyLight, and the light spreads from them through transparent
+// blocks, one level darker for each block, like sky light in Minecraft.
+// Solid, opaque blocks are dark.
+// TODO Block lights, such as torches and lava
+// You must lock Dim yourself
+func (d *Dimension) LightAt(pos Vec3) int {
+	if !d.lightPasses(pos) {
+		return 0
+	}
+
+	// The highest block which stops light in each column, which is found
+	// once per column
+	tops := make(map[[2]int]int)
+	canSeeSky := func(v Vec3) bool {
+		top, ok := tops[[2]int{v.X, v.Z}]
+		if !ok {
+			top = d.lightTop(v.X, v.Z, pos.Y, pos.Y-MAX_LIGHT)
+			tops[[2]int{v.X, v.Z}] = top
+		}
+		return v.Y > top
+	}
+
+	// Search outwards, so the first block found which can see the sky is the
+	// closest
+	visited := map[Vec3]bool{pos: true}
+	queue := []Vec3{pos}
+	for level := d.SkyLight(); level > 0 && len(queue) > 0; level-- {
+		var next []Vec3
+		for _, v := range queue {
+			if canSeeSky(v) {
+				return level
+			}
+
+			for _, dir := range faceDirections {
+				n := v.Add(dir)
+				if !visited[n] && d.lightPasses(n) {
+					visited[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		queue = next
+	}
+	return 0
+}
+
+// Whether light can pass through the block
+func (d *Dimension) lightPasses(pos Vec3) bool {
+	t := d.GetBlockAt(pos).Type
+	return t == nil || t.Transparent
+}
+
+// The height of the highest block in the column which stops light, looking
+// no lower than the floor. Returns below the floor if there are none.
+// Chunks which aren't loaded are treated as empty, as for CanSeeSky, so the
+// top of the column is found by looking up from the chunk at y.
+func (d *Dimension) lightTop(x, z, y, floor int) int {
+	top := NewVec3(x, y, z)
+	if d.GetChunkContaining(top) == nil {
+		return floor - 1
+	}
+	for d.GetChunkContaining(top.Add(Vec3{Y: 16})) != nil {
+		top.Y += 16
+	}
+
+	for v := ChunkPosition(top).Y + 15; v >= floor; v-- {
+		if !d.lightPasses(NewVec3(x, v, z)) {
+			return v
+		}
+	}
+	return floor - 1
+}
+
+// The kind of area a column of the world is in, which decides what spawns
+// there
+type Biome string
+
+// The biome of terrain which doesn't vary, which the whole world is for now
+const BiomePlains Biome = "mc:plains"
+
+// A group of mobs which share a limit on how many can be spawned around
+// each player
+type SpawnCategory struct {
+	Name string
+	// The most mobs of the category which can be around a player before more
+	// stop spawning
+	Cap int
+}
+
+var (
+	SpawnPassive = &SpawnCategory{Name: "passive", Cap: 10}
+	SpawnHostile = &SpawnCategory{Name: "hostile", Cap: 20}
+)
+
+// In the order spawning is attempted
+var SpawnCategories = []*SpawnCategory{SpawnPassive, SpawnHostile}
+
+type SpawnPlacement uint8
+
+const (
+	// Where the sky can be seen
+	SpawnSurface SpawnPlacement = iota
+	// Where the sky can't be seen
+	SpawnUnderground
+)
+
+// Where and how an entity type will spawn naturally
+type SpawnRule struct {
+	// The registered name of the entity type to spawn
+	Entity    string
+	Category  *SpawnCategory
+	Placement SpawnPlacement
+
+	// The range of light levels the entity can spawn in, inclusive
+	MinLight, MaxLight int
+	// The biomes the entity can spawn in, or any if empty
+	Biomes []Biome
+	// The blocks the entity can spawn on, or any if empty
+	Ground []string
+
+	// The range of how many entities will be spawned together, inclusive
+	MinGroup, MaxGroup int
+	// How often the rule is picked compared to other rules of its category
+	Weight int
+}
+
+// Whether an entity with the AABB can spawn at the position, where the
+// position is the block its feet are in at the corner with the smallest
+// coordinates
+// You must lock Dim yourself
+func (r *SpawnRule) CanSpawnAt(dim *Dimension, pos Vec3, aabb mgl32.Vec3, biome Biome) bool {
+	if len(r.Biomes) > 0 {
+		found := false
+		for _, v := range r.Biomes {
+			if v == biome {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if !CanStandAt(dim, pos, aabb) {
+		return false
+	}
+
+	if len(r.Ground) > 0 {
+		ground := dim.GetBlockAt(pos.Add(Vec3{Y: -1}))
+		found := false
+		for _, v := range r.Ground {
+			if ground.Type != nil && ground.Type.Name == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if dim.CanSeeSky(pos) != (r.Placement == SpawnSurface) {
+		return false
+	}
+
+	light := dim.LightAt(pos)
+	return light >= r.MinLight && light <= r.MaxLight
+}
+
+// Finds the first spawn rule for the entity type, if it spawns naturally.
+// Every rule for a type should be of the same category.
+func GetSpawnRule(entity string) *SpawnRule {
+	for _, v := range SpawnRules {
+		if v.Entity == entity {
+			return v
+		}
+	}
+	return nil
+}
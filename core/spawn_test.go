@@ -0,0 +1,80 @@
+package core
+
+import "testing"
+
+func TestLightAt(t *testing.T) {
+	d := newTestDimension()
+	fillTestBlocks(d, NewVec3(-16, 0, -16), NewVec3(47, 0, 15), testStone)
+	// A roof from x = 0 onwards, open at x = -1. The light can also get in
+	// from the ends of the chunks.
+	fillTestBlocks(d, NewVec3(0, 3, -16), NewVec3(47, 3, 15), testStone)
+
+	tests := []struct {
+		pos   Vec3
+		light int
+	}{
+		{NewVec3(-1, 1, 0), MAX_LIGHT},
+		{NewVec3(0, 1, 0), MAX_LIGHT - 1},
+		{NewVec3(5, 2, 0), MAX_LIGHT - 6},
+		{NewVec3(20, 1, 0), 0},
+		// Inside solid blocks
+		{NewVec3(5, 0, 0), 0},
+	}
+	for _, v := range tests {
+		if l := d.LightAt(v.pos); l != v.light {
+			t.Errorf("light at %v should be %d, got %d", v.pos, v.light, l)
+		}
+	}
+
+	// Slabs let light through
+	d.SetBlockAt(Block{Position: NewVec3(10, 3, 0), Type: testSlab})
+	if l := d.LightAt(NewVec3(10, 1, 0)); l != MAX_LIGHT {
+		t.Errorf("light under a slab should be %d, got %d", MAX_LIGHT, l)
+	}
+}
+
+func TestLightAtNight(t *testing.T) {
+	d := newTestDimension()
+	fillTestBlocks(d, NewVec3(-16, 0, -16), NewVec3(15, 0, 15), testStone)
+	d.Ticks = NIGHT_START
+
+	if l := d.LightAt(NewVec3(0, 1, 0)); l != NIGHT_SKY_LIGHT {
+		t.Errorf("light under the sky at night should be %d, got %d", NIGHT_SKY_LIGHT, l)
+	}
+
+	d.Ticks = DAY_LENGTH + NIGHT_END
+	if l := d.LightAt(NewVec3(0, 1, 0)); l != MAX_LIGHT {
+		t.Errorf("light under the sky in the morning should be %d, got %d", MAX_LIGHT, l)
+	}
+}
+
+func TestCanSpawnAt(t *testing.T) {
+	d := newTestDimension()
+	fillTestBlocks(d, NewVec3(-16, 0, -16), NewVec3(15, 0, 15), testStone)
+	pos := NewVec3(0, 1, 0)
+
+	dark := &SpawnRule{Placement: SpawnSurface, MinLight: 0, MaxLight: 7}
+	if dark.CanSpawnAt(d, pos, testPlayerAABB, BiomePlains) {
+		t.Error("dark surface rule shouldn't spawn in the day")
+	}
+	d.Ticks = NIGHT_START
+	if !dark.CanSpawnAt(d, pos, testPlayerAABB, BiomePlains) {
+		t.Error("dark surface rule should spawn at night")
+	}
+	if (&SpawnRule{Placement: SpawnUnderground, MaxLight: 7}).CanSpawnAt(d, pos, testPlayerAABB, BiomePlains) {
+		t.Error("underground rule shouldn't spawn under the sky")
+	}
+
+	dark.Biomes = []Biome{"test:desert"}
+	if dark.CanSpawnAt(d, pos, testPlayerAABB, BiomePlains) {
+		t.Error("rule shouldn't spawn outside its biomes")
+	}
+	if !dark.CanSpawnAt(d, pos, testPlayerAABB, "test:desert") {
+		t.Error("rule should spawn in its biomes")
+	}
+
+	dark.Ground = []string{testSlab.Name}
+	if dark.CanSpawnAt(d, pos, testPlayerAABB, "test:desert") {
+		t.Error("rule shouldn't spawn on other ground")
+	}
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// EntityStore holds the entities of a dimension, indexed by their IDs.
+// Queries for the entities satisfying an interface are cached, and kept up to
+// date as entities are added and removed, so systems don't need to check every
+// entity each frame. The zero value is an empty store.
+// You must lock Dim yourself
+type EntityStore struct {
+	entities []Entity
+	index    map[uuid.UUID]int
+	queries  map[reflect.Type]queryCache
+	spatial  spatialIndex
+
+	// While iterating with Each, changes are held back until the iteration
+	// has finished, so the slices being iterated over don't change
+	iterating int
+	pending   []pendingChange
+	removing  map[uuid.UUID]bool
+}
+
+type pendingChange struct {
+	add    Entity
+	remove uuid.UUID
+	// Set for adds of entities which were removed again before the flush
+	cancelled bool
+}
+
+type queryCache interface {
+	add(e Entity)
+	remove(id uuid.UUID)
+}
+
+// The entities satisfying V, in no particular order
+type queryResult[V any] struct {
+	items []V
+	// The IDs of the items at the same indices, and the reverse
+	ids   []uuid.UUID
+	index map[uuid.UUID]int
+}
+
+func (r *queryResult[V]) add(e Entity) {
+	v, ok := e.(V)
+	if !ok {
+		return
+	}
+
+	r.index[e.GetID()] = len(r.items)
+	r.items = append(r.items, v)
+	r.ids = append(r.ids, e.GetID())
+}
+
+// Swaps the last item into the place of the removed one
+func (r *queryResult[V]) remove(id uuid.UUID) {
+	i, ok := r.index[id]
+	if !ok {
+		return
+	}
+
+	last := len(r.items) - 1
+	r.items[i] = r.items[last]
+	r.ids[i] = r.ids[last]
+	r.index[r.ids[i]] = i
+
+	var zero V
+	r.items[last] = zero
+	r.items = r.items[:last]
+	r.ids = r.ids[:last]
+	delete(r.index, id)
+}
+
+func (s *EntityStore) Len() int {
+	return len(s.entities) - len(s.removing)
+}
+
+// Returns the entity with the ID, or nil if it isn't in the store
+func (s *EntityStore) Get(id uuid.UUID) Entity {
+	i, ok := s.index[id]
+	if !ok || s.removing[id] {
+		return nil
+	}
+	return s.entities[i]
+}
+
+// Adds the entity to the store, replacing any entity with the same ID.
+// While iterating with Each, the entity is added once the iteration finishes.
+func (s *EntityStore) Add(e Entity) {
+	if s.iterating > 0 {
+		s.pending = append(s.pending, pendingChange{add: e})
+		return
+	}
+
+	if s.index == nil {
+		s.index = make(map[uuid.UUID]int)
+	}
+	if _, ok := s.index[e.GetID()]; ok {
+		s.remove(e.GetID())
+	}
+
+	s.index[e.GetID()] = len(s.entities)
+	s.entities = append(s.entities, e)
+	s.spatial.add(e)
+	for _, q := range s.queries {
+		q.add(e)
+	}
+}
+
+// Removes the entity with the ID, returning false if it wasn't in the store.
+// While iterating with Each, the entity is skipped for the rest of the
+// iteration, and removed once it finishes. Entities added during the
+// iteration are never added.
+func (s *EntityStore) Remove(id uuid.UUID) bool {
+	cancelled := false
+	for i, v := range s.pending {
+		if v.add != nil && !v.cancelled && v.add.GetID() == id {
+			s.pending[i].cancelled = true
+			cancelled = true
+		}
+	}
+
+	if _, ok := s.index[id]; !ok || s.removing[id] {
+		return cancelled
+	}
+
+	if s.iterating > 0 {
+		if s.removing == nil {
+			s.removing = make(map[uuid.UUID]bool)
+		}
+		s.removing[id] = true
+		s.pending = append(s.pending, pendingChange{remove: id})
+		return true
+	}
+
+	s.remove(id)
+	return true
+}
+
+func (s *EntityStore) remove(id uuid.UUID) {
+	i := s.index[id]
+	last := len(s.entities) - 1
+	s.entities[i] = s.entities[last]
+	s.index[s.entities[i].GetID()] = i
+	s.entities[last] = nil
+	s.entities = s.entities[:last]
+	delete(s.index, id)
+	s.spatial.remove(id)
+
+	for _, q := range s.queries {
+		q.remove(id)
+	}
+}
+
+// Makes the changes held back while iterating
+func (s *EntityStore) flush() {
+	pending := s.pending
+	s.pending = nil
+	s.removing = nil
+
+	for _, v := range pending {
+		if v.cancelled {
+			continue
+		} else if v.add != nil {
+			s.Add(v.add)
+		} else {
+			s.Remove(v.remove)
+		}
+	}
+}
+
+func getQuery[V any](s *EntityStore) *queryResult[V] {
+	t := reflect.TypeOf((*V)(nil)).Elem()
+	if q, ok := s.queries[t]; ok {
+		return q.(*queryResult[V])
+	}
+
+	r := &queryResult[V]{index: make(map[uuid.UUID]int)}
+	for _, e := range s.entities {
+		r.add(e)
+	}
+
+	if s.queries == nil {
+		s.queries = make(map[reflect.Type]queryCache)
+	}
+	s.queries[t] = r
+	return r
+}
+
+// Returns the entities in the store satisfying V, such as a component
+// interface. The slice is shared, so must not be modified, and is only valid
+// until the next change to the store. Entities being removed during Each are
+// still included until the iteration finishes.
+func Query[V any](s *EntityStore) []V {
+	return getQuery[V](s).items
+}
+
+// Calls fn for every entity in the store satisfying V. Entities can safely be
+// added and removed by fn: removed entities are skipped, and added entities
+// aren't visited.
+func Each[V any](s *EntityStore, fn func(V)) {
+	r := getQuery[V](s)
+
+	s.iterating++
+	for i, v := range r.items {
+		if s.removing[r.ids[i]] {
+			continue
+		}
+		fn(v)
+	}
+	s.iterating--
+
+	if s.iterating == 0 && len(s.pending) > 0 {
+		s.flush()
+	}
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// A body for testing entity stores and physics
+type testBody struct {
+	EntityBase
+	PositionComp
+	PhysicsComp
+}
+
+func (b *testBody) GetTypeName() string {
+	return "test:body"
+}
+
+func newTestBody(pos, aabb mgl32.Vec3) *testBody {
+	return &testBody{
+		EntityBase:   EntityBase{ID: uuid.New()},
+		PositionComp: PositionComp{Position: pos},
+		PhysicsComp:  PhysicsComp{AABB: aabb},
+	}
+}
+
+func TestStoreRemoveDuringEach(t *testing.T) {
+	var s EntityStore
+	a := newTestBody(mgl32.Vec3{}, testPlayerAABB)
+	s.Add(a)
+
+	var added *testBody
+	Each(&s, func(*testBody) {
+		if !s.Remove(a.GetID()) {
+			t.Error("removing an entity during Each should succeed")
+		}
+		if s.Get(a.GetID()) != nil {
+			t.Error("entity removed during Each should be gone")
+		}
+
+		// Spawned and despawned in the same tick
+		added = newTestBody(mgl32.Vec3{}, testPlayerAABB)
+		s.Add(added)
+		if !s.Remove(added.GetID()) {
+			t.Error("removing an entity added during Each should succeed")
+		}
+		if s.Remove(added.GetID()) {
+			t.Error("removing an entity twice should fail")
+		}
+	})
+
+	if s.Len() != 0 || s.Get(a.GetID()) != nil || s.Get(added.GetID()) != nil {
+		t.Fatalf("store should be empty after Each, has %d entities", s.Len())
+	}
+	if len(Query[*testBody](&s)) != 0 || len(s.InRadius(mgl32.Vec3{}, 10)) != 0 {
+		t.Fatal("queries should be empty after Each")
+	}
+}
+
+func TestStoreAddDuringEach(t *testing.T) {
+	var s EntityStore
+	s.Add(newTestBody(mgl32.Vec3{}, testPlayerAABB))
+
+	visited := 0
+	var added *testBody
+	Each(&s, func(*testBody) {
+		visited++
+		added = newTestBody(mgl32.Vec3{}, testPlayerAABB)
+		s.Add(added)
+	})
+
+	if visited != 1 {
+		t.Errorf("entities added during Each shouldn't be visited, visited %d", visited)
+	}
+	if s.Get(added.GetID()) == nil || s.Len() != 2 {
+		t.Errorf("entity added during Each should be added after, store has %d", s.Len())
+	}
+}
+
+// Enough entities for a busy server
+const benchmarkEntities = 50000
+
+// Fills a store with bodies spread over a square of chunks around the origin
+func newBenchmarkStore() *EntityStore {
+	r := rand.New(rand.NewSource(1))
+	s := new(EntityStore)
+	for i := 0; i < benchmarkEntities; i++ {
+		pos := mgl32.Vec3{r.Float32()*1024 - 512, r.Float32() * 128, r.Float32()*1024 - 512}
+		s.Add(newTestBody(pos, testPlayerAABB))
+	}
+	return s
+}
+
+func BenchmarkQuery(b *testing.B) {
+	s := newBenchmarkStore()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		n := 0
+		for _, v := range Query[PhysicsFace](s) {
+			n += int(v.GetPhysicsComp().AABB.Y())
+		}
+	}
+}
+
+func BenchmarkEach(b *testing.B) {
+	s := newBenchmarkStore()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		n := 0
+		Each(s, func(v PhysicsFace) {
+			n += int(v.GetPhysicsComp().AABB.Y())
+		})
+	}
+}
+
+func BenchmarkInRadius(b *testing.B) {
+	s := newBenchmarkStore()
+	r := rand.New(rand.NewSource(2))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		centre := mgl32.Vec3{r.Float32()*1024 - 512, 64, r.Float32()*1024 - 512}
+		s.InRadius(centre, 16)
+	}
+}
+
+func BenchmarkMoved(b *testing.B) {
+	s := newBenchmarkStore()
+	bodies := Query[*testBody](s)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		v := bodies[i%len(bodies)]
+		v.Position[0] += 1
+		s.Moved(v)
+	}
+}
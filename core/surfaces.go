@@ -0,0 +1,123 @@
+package core
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The slipperiness of most blocks. Higher is more slippery, up to 1.
+const DEFAULT_SLIPPERINESS = 0.6
+
+// The slipperiness of the air, which bodies which aren't on the ground have
+const AIR_SLIPPERINESS = 1.0
+
+// The fastest bodies can fall or move sideways while climbing, in m/s
+const MAX_CLIMBING_SPEED = 3
+
+// The speed bodies climb up at when walking into something they can climb,
+// in m/s
+const CLIMBING_SPEED = 4
+
+// How slippery the block is, for bodies standing on it
+func (t *BlockType) GetSlipperiness() float32 {
+	if t == nil || t.Slipperiness == 0 {
+		return DEFAULT_SLIPPERINESS
+	}
+	return t.Slipperiness
+}
+
+// How much bodies on or in the block are slowed down
+func (t *BlockType) GetSpeedFactor() float32 {
+	if t == nil || t.SpeedFactor == 0 {
+		return 1
+	}
+	return t.SpeedFactor
+}
+
+// How slippery the ground under the body is, or the air if it isn't on the
+// ground
+func (p *PhysicsComp) Slipperiness() float32 {
+	if !p.onGround {
+		return AIR_SLIPPERINESS
+	}
+	return p.ground.GetSlipperiness()
+}
+
+// Slows the body down horizontally for a tick, by the slipperiness of what it's
+// on, as for players and mobs walking
+func (p *PhysicsComp) ApplyFriction() {
+	f := 0.91 * p.Slipperiness()
+	p.Velocity[0] *= f
+	p.Velocity[2] *= f
+}
+
+// Whether the body is in something it can climb, such as a ladder
+func (p *PhysicsComp) Climbing() bool {
+	return p.climbing
+}
+
+// The block a body at the position is standing on. Bodies standing on slabs
+// are in the slab's block.
+func blockUnder(dim *Dimension, pos, aabb mgl32.Vec3) Block {
+	centre := pos.Add(mgl32.Vec3{aabb.X() / 2, -0.5, aabb.Z() / 2})
+	return dim.GetBlockAt(NewVec3FromFloat(centre))
+}
+
+// The block a body at the position has its feet in
+func blockAtFeet(dim *Dimension, pos, aabb mgl32.Vec3) Block {
+	centre := pos.Add(mgl32.Vec3{aabb.X() / 2, 0, aabb.Z() / 2})
+	return dim.GetBlockAt(NewVec3FromFloat(centre))
+}
+
+// Limits the body's speed while climbing, before it moves. Bodies staying on
+// edges, such as sneaking players, don't slide down.
+func applyClimbing(dim *Dimension, e *PhysicsComp, pos mgl32.Vec3) {
+	b := blockAtFeet(dim, pos, e.AABB)
+	e.climbing = b.Type != nil && b.Type.Climbable && !e.NoClip
+	if !e.climbing {
+		return
+	}
+
+	for _, i := range []int{0, 2} {
+		if e.Velocity[i] > MAX_CLIMBING_SPEED {
+			e.Velocity[i] = MAX_CLIMBING_SPEED
+		} else if e.Velocity[i] < -MAX_CLIMBING_SPEED {
+			e.Velocity[i] = -MAX_CLIMBING_SPEED
+		}
+	}
+	if e.Velocity[1] < -MAX_CLIMBING_SPEED {
+		e.Velocity[1] = -MAX_CLIMBING_SPEED
+	}
+	if e.StayOnEdges && e.Velocity[1] < 0 {
+		e.Velocity[1] = 0
+	}
+
+	// Climbing down slowly doesn't hurt
+	e.landingSpeed = 0
+}
+
+// Applies the effects of the blocks the body is on and in after it moves, such
+// as bouncing off bouncy blocks and slowing down in sticky ones.
+// landing is the speed the body just landed at, or 0 if it didn't land.
+func applySurface(dim *Dimension, e *PhysicsComp, pos mgl32.Vec3, landing float32, blocked [3]bool) {
+	e.ground = blockUnder(dim, pos, e.AABB).Type
+
+	// Climb up when walking into something while climbing
+	if e.climbing && (blocked[0] || blocked[2]) {
+		e.Velocity[1] = CLIMBING_SPEED
+	}
+
+	// Bounce, unless trying to stay still, such as sneaking players
+	if landing > 0 && e.ground != nil && e.ground.Bounciness > 0 && !e.StayOnEdges {
+		e.Velocity[1] = landing * e.ground.Bounciness
+		e.onGround = false
+	}
+
+	// Blocks at the feet slow the body down, such as cobwebs, or else the
+	// block being stood on, such as soul sand
+	factor := blockAtFeet(dim, pos, e.AABB).Type.GetSpeedFactor()
+	if factor == 1 && e.onGround {
+		factor = e.ground.GetSpeedFactor()
+	}
+	e.Velocity[0] *= factor
+	e.Velocity[2] *= factor
+}
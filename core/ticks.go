@@ -0,0 +1,152 @@
+package core
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// A tick scheduled for the block at the position, such as for fluid flowing.
+// The delay is what's left rather than when it runs, so the tick doesn't
+// depend on the dimension's Ticks when it is saved and loaded.
+type ScheduledTick struct {
+	Position Vec3
+	// The number of ticks left until it runs
+	Delay int
+}
+
+// What the block tick hooks can do to the world
+type BlockTickContext struct {
+	Dim  *Dimension
+	Rand *rand.Rand
+	// Changes the block, updating clients and scheduling the ticks of the
+	// blocks around it
+	SetBlock func(b Block)
+}
+
+// Schedules the block at the position to tick after the delay. Does nothing
+// if it's already scheduled, or isn't in a loaded chunk.
+// You must lock Dim yourself
+func (d *Dimension) ScheduleTick(pos Vec3, delay int) {
+	chk := d.GetChunkContaining(pos)
+	if chk == nil {
+		return
+	}
+
+	for _, v := range chk.ScheduledTicks {
+		if v.Position == pos {
+			return
+		}
+	}
+	chk.ScheduledTicks = append(chk.ScheduledTicks, ScheduledTick{
+		Position: pos,
+		Delay:    delay,
+	})
+}
+
+// Schedules the ticks of the block at the position and those next to it,
+// which have an UpdateTickDelay, after the block changes
+// You must lock Dim yourself
+func (d *Dimension) ScheduleUpdateTicks(pos Vec3) {
+	for _, v := range append([]Vec3{{}}, faceDirections...) {
+		b := d.GetBlockAt(pos.Add(v))
+		if b.Type != nil && b.Type.UpdateTickDelay > 0 {
+			d.ScheduleTick(b.Position, b.Type.UpdateTickDelay)
+		}
+	}
+}
+
+// In a set order, unlike FaceDirection
+var faceDirections = []Vec3{
+	FaceDirection[FaceTop], FaceDirection[FaceBottom],
+	FaceDirection[FaceLeft], FaceDirection[FaceRight],
+	FaceDirection[FaceFront], FaceDirection[FaceBack],
+}
+
+// Runs a tick of the dimension's blocks. Scheduled ticks which are due run
+// first, then randomTicks blocks picked at
+// random from each chunk have their random ticks.
+// TODO Only tick the chunks around players, once chunks are unloaded
+// You must lock Dim yourself
+func BlockTickSystem(ctx *BlockTickContext, randomTicks int) {
+	d := ctx.Dim
+
+	var due []ScheduledTick
+	for _, chk := range d.Chunks {
+		if len(chk.ScheduledTicks) == 0 {
+			continue
+		}
+
+		var left []ScheduledTick
+		for _, v := range chk.ScheduledTicks {
+			v.Delay--
+			if v.Delay <= 0 {
+				due = append(due, v)
+			} else {
+				left = append(left, v)
+			}
+		}
+		chk.ScheduledTicks = left
+	}
+
+	// Run in the same order every time, as what happens can depend on it
+	sort.Slice(due, func(i, j int) bool {
+		a, b := due[i], due[j]
+		if a.Delay != b.Delay {
+			return a.Delay < b.Delay
+		}
+		return positionLess(a.Position, b.Position)
+	})
+	for _, v := range due {
+		if b := d.GetBlockAt(v.Position); b.Type != nil && b.Type.OnTick != nil {
+			b.Type.OnTick(ctx, b)
+		}
+	}
+
+	if randomTicks <= 0 {
+		return
+	}
+
+	// Pick from the chunks in the same order every time, so the blocks
+	// picked only depend on ctx.Rand
+	var chunks []*Chunk
+	for _, chk := range d.Chunks {
+		if chk.hasRandomTicks() {
+			chunks = append(chunks, chk)
+		}
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return positionLess(chunks[i].Position, chunks[j].Position)
+	})
+
+	for _, chk := range chunks {
+		for i := 0; i < randomTicks; i++ {
+			pos := NewVec3(ctx.Rand.Intn(16), ctx.Rand.Intn(16), ctx.Rand.Intn(16))
+			if b := chk.GetBlockAt(pos); b.Type != nil && b.Type.OnRandomTick != nil {
+				b.Type.OnRandomTick(ctx, b)
+			}
+		}
+	}
+}
+
+// Whether any blocks in the chunk have random ticks, so chunks of only air
+// and stone can be skipped
+func (c *Chunk) hasRandomTicks() bool {
+	for _, v := range c.BlockPalette {
+		if t := BlockRegistry[v]; t != nil && t.OnRandomTick != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Orders positions by height, then x and z
+func positionLess(a, b Vec3) bool {
+	switch {
+	case a.Y != b.Y:
+		return a.Y < b.Y
+	case a.X != b.X:
+		return a.X < b.X
+	default:
+		return a.Z < b.Z
+	}
+}
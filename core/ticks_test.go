@@ -0,0 +1,107 @@
+package core
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestScheduledTickDelay(t *testing.T) {
+	var ticked []Vec3
+	clock := AddBlockToRegistry(&BlockType{Name: "test:clock", OnTick: func(ctx *BlockTickContext, b Block) {
+		ticked = append(ticked, b.Position)
+	}})
+
+	d := newTestDimension()
+	fillTestBlocks(d, NewVec3(0, 0, 0), NewVec3(1, 0, 0), clock)
+	d.ScheduleTick(NewVec3(0, 0, 0), 3)
+	d.ScheduleTick(NewVec3(1, 0, 0), 1)
+	// Already scheduled
+	d.ScheduleTick(NewVec3(1, 0, 0), 5)
+
+	ctx := &BlockTickContext{Dim: d, Rand: rand.New(rand.NewSource(1))}
+	want := [][]Vec3{{NewVec3(1, 0, 0)}, nil, {NewVec3(0, 0, 0)}, nil}
+	for i, v := range want {
+		ticked = nil
+		BlockTickSystem(ctx, 0)
+		if len(ticked) != len(v) || (len(v) > 0 && ticked[0] != v[0]) {
+			t.Fatalf("tick %d should run %v, ran %v", i+1, v, ticked)
+		}
+	}
+}
+
+func TestSaveChunkTicks(t *testing.T) {
+	ticks := 0
+	clock := AddBlockToRegistry(&BlockType{Name: "test:saved_clock", OnTick: func(ctx *BlockTickContext, b Block) {
+		ticks++
+	}})
+
+	d := newTestDimension()
+	fillTestBlocks(d, NewVec3(0, 0, 0), NewVec3(0, 0, 0), clock)
+	d.ScheduleTick(NewVec3(0, 0, 0), 5)
+	ctx := &BlockTickContext{Dim: d, Rand: rand.New(rand.NewSource(1))}
+	BlockTickSystem(ctx, 0)
+	BlockTickSystem(ctx, 0)
+
+	data, err := SaveChunk(d.GetChunkContaining(NewVec3(0, 0, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chk, err := LoadChunk(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The loaded tick runs after the delay which was left
+	loaded := newTestDimension()
+	loaded.Chunks[chk.Position] = chk
+	ctx.Dim = loaded
+	for i := 1; i <= 3; i++ {
+		BlockTickSystem(ctx, 0)
+		if want := i / 3; ticks != want {
+			t.Fatalf("%d ticks after loading, ran %d scheduled ticks, want %d", i, ticks, want)
+		}
+	}
+	if b := loaded.GetBlockAt(NewVec3(0, 0, 0)); b.Type != clock {
+		t.Errorf("loaded block is %v, want the clock", b.Type)
+	}
+}
+
+func TestRandomTicks(t *testing.T) {
+	var ticked []Vec3
+	random := AddBlockToRegistry(&BlockType{Name: "test:random", OnRandomTick: func(ctx *BlockTickContext, b Block) {
+		ticked = append(ticked, b.Position)
+	}})
+
+	// Two chunks of blocks with random ticks, and one of stone which is
+	// skipped
+	run := func() []Vec3 {
+		d := newTestDimension()
+		fillTestBlocks(d, NewVec3(0, 0, 0), NewVec3(31, 15, 15), random)
+		fillTestBlocks(d, NewVec3(0, 16, 0), NewVec3(15, 31, 15), testStone)
+
+		ticked = nil
+		BlockTickSystem(&BlockTickContext{Dim: d, Rand: rand.New(rand.NewSource(1))}, 3)
+		return ticked
+	}
+
+	first := run()
+	if len(first) != 6 {
+		t.Fatalf("ran %d random ticks, want 3 in each of 2 chunks", len(first))
+	}
+	for i, v := range first {
+		if want := NewVec3(i/3*16, 0, 0); ChunkPosition(v) != want {
+			t.Errorf("random tick %d was in the chunk at %v, want %v", i, ChunkPosition(v), want)
+		}
+	}
+
+	// The same blocks are picked with the same seed, whatever order the
+	// chunks are stored in
+	for i := 0; i < 10; i++ {
+		again := run()
+		for j := range first {
+			if again[j] != first[j] {
+				t.Fatalf("run %d ticked %v, want %v", i+2, again, first)
+			}
+		}
+	}
+}
@@ -78,7 +78,7 @@ func TraceRay(dir mgl32.Vec3, pos mgl32.Vec3, reach float32,
 	// Step each axis by one unit from the border until max reach, adding each
 	// scalar to the list
 	var steps []float64
-	if !math.IsNaN(float64(lenStepX)) {
+	if dir.X() != 0 {
 		// Substep to border of voxel, and get step dir
 		stepDir := 1
 		substep := float32(math.Ceil(float64(pos.X()))) - pos.X()
@@ -99,7 +99,7 @@ func TraceRay(dir mgl32.Vec3, pos mgl32.Vec3, reach float32,
 			i += stepDir
 		}
 	}
-	if !math.IsNaN(float64(lenStepY)) {
+	if dir.Y() != 0 {
 		// Substep to border of voxel, and get step dir
 		stepDir := 1
 		substep := float32(math.Ceil(float64(pos.Y()))) - pos.Y()
@@ -120,7 +120,7 @@ func TraceRay(dir mgl32.Vec3, pos mgl32.Vec3, reach float32,
 			i += stepDir
 		}
 	}
-	if !math.IsNaN(float64(lenStepZ)) {
+	if dir.Z() != 0 {
 		// Substep to border of voxel, and get step dir
 		stepDir := 1
 		substep := float32(math.Ceil(float64(pos.Z()))) - pos.Z()
@@ -178,25 +178,26 @@ func TraceRay(dir mgl32.Vec3, pos mgl32.Vec3, reach float32,
 	}
 }
 
-func FaceFromSubvoxel(sv mgl32.Vec3) BlockFace {
+// Returns the face of a whole block which the subvoxel hit is on
+func faceFromSubvoxel(sv mgl32.Vec3) (BlockFace, bool) {
 	if mgl32.FloatEqualThreshold(sv.Y(), 1, 0.001) {
-		return FaceTop
+		return FaceTop, true
 	}
 	if mgl32.FloatEqualThreshold(sv.Y(), 0, 0.001) {
-		return FaceBottom
+		return FaceBottom, true
 	}
 	if mgl32.FloatEqualThreshold(sv.X(), 0, 0.001) {
-		return FaceLeft
+		return FaceLeft, true
 	}
 	if mgl32.FloatEqualThreshold(sv.X(), 1, 0.001) {
-		return FaceRight
+		return FaceRight, true
 	}
 	if mgl32.FloatEqualThreshold(sv.Z(), 1, 0.001) {
-		return FaceFront
+		return FaceFront, true
 	}
 	if mgl32.FloatEqualThreshold(sv.Z(), 0, 0.001) {
-		return FaceBack
+		return FaceBack, true
 	}
 
-	panic("FaceFromSubvoxel: vector not on face")
+	return 0, false
 }
@@ -44,8 +44,50 @@ type BlockType struct {
 	Transparent bool
 	RenderType  RenderBlockType
 
+	// The time it takes to dig the block by hand in survival, in seconds
+	Hardness float32
+
 	// The type of the entity that will be linked with with block.
 	LinkWithEntity string
+
+	// The boxes outlined and hit when looking at the block, relative to its
+	// corner. nil is a full block.
+	SelectionBoxes []BlockBox
+	// The boxes bodies collide with, if different to SelectionBoxes
+	CollisionBoxes []BlockBox
+	// Set for blocks which can be walked through, such as plants
+	NoCollision bool
+
+	// How slippery the block is to walk on, such as 0.98 for ice. 0 is
+	// DEFAULT_SLIPPERINESS.
+	Slipperiness float32
+	// Multiplies the speed of bodies on or in the block each tick, such as 0.4
+	// for soul sand. 0 is no change.
+	SpeedFactor float32
+	// The fraction of their landing speed bodies bounce back up at, such as 1
+	// for slime
+	Bounciness float32
+	// Set for blocks bodies can climb up, such as ladders
+	Climbable bool
+
+	// Translucent blocks are drawn after all other blocks, so what's behind
+	// them can be seen through them, such as water. They should also be
+	// Transparent.
+	Translucent bool
+
+	// The fluid the block is part of, if any, and its level. Fluid blocks are
+	// registered by AddFluidToRegistry.
+	Fluid      *FluidType
+	FluidLevel int
+
+	// Run when a tick scheduled for the block is due
+	OnTick func(ctx *BlockTickContext, b Block)
+	// Run when the block is picked for a random tick, such as for grass
+	// spreading
+	OnRandomTick func(ctx *BlockTickContext, b Block)
+	// If set, a tick is scheduled this many ticks after the block or one next
+	// to it changes, such as for fluids flowing
+	UpdateTickDelay int
 }
 
 type RenderBlockType interface {
@@ -65,15 +107,29 @@ type Dimension struct {
 	Chunks map[Vec3]*Chunk
 
 	// Entities include everything in the dimension, including chunks, mobs, etc.
-	Entities []Entity
+	Entities EntityStore
+
+	// Paths which will be invalidated when one of their blocks changes, by
+	// each of their blocks
+	paths map[Vec3]map[*Path]struct{}
+
+	// The number of ticks the dimension has run, such as for when mobs were
+	// last hurt. Incremented at the start of each tick, and saved with the
+	// world.
+	Ticks int
 }
 
-func (d *Dimension) GetChunkContaining(pos Vec3) *Chunk {
-	return d.Chunks[NewVec3(
+// The position of the chunk containing the block position
+func ChunkPosition(pos Vec3) Vec3 {
+	return NewVec3(
 		FlooredDivision(pos.X, 16)*16,
 		FlooredDivision(pos.Y, 16)*16,
 		FlooredDivision(pos.Z, 16)*16,
-	)]
+	)
+}
+
+func (d *Dimension) GetChunkContaining(pos Vec3) *Chunk {
+	return d.Chunks[ChunkPosition(pos)]
 }
 
 func (d *Dimension) GetBlockAt(pos Vec3) Block {
@@ -133,4 +189,7 @@ func (d *Dimension) SetBlockAt(b Block) {
 	y := FlooredRemainder(b.Position.Y, 16)
 	z := FlooredRemainder(b.Position.Z, 16)
 	chk.SetBlockAt(NewVec3(x, y, z), b)
+	chk.Changed = true
+
+	d.invalidatePaths(b.Position)
 }
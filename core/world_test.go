@@ -0,0 +1,36 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Block types for building test dimensions, without depending on the
+// registered blocks of the game
+var (
+	testStone = AddBlockToRegistry(&BlockType{Name: "test:stone"})
+	testSlab  = AddBlockToRegistry(&BlockType{Name: "test:slab", Transparent: true, SelectionBoxes: SLAB_SHAPE})
+)
+
+// The size of players, for testing bodies and paths
+var testPlayerAABB = mgl32.Vec3{0.6, 1.8, 0.6}
+
+func newTestDimension() *Dimension {
+	return &Dimension{Chunks: make(map[Vec3]*Chunk), Lock: new(sync.RWMutex)}
+}
+
+// Sets every block from min to max inclusive, loading the chunks they are in
+func fillTestBlocks(d *Dimension, min, max Vec3, t *BlockType) {
+	for x := min.X; x <= max.X; x++ {
+		for y := min.Y; y <= max.Y; y++ {
+			for z := min.Z; z <= max.Z; z++ {
+				pos := NewVec3(x, y, z)
+				if d.GetChunkContaining(pos) == nil {
+					d.Chunks[ChunkPosition(pos)] = NewChunk(ChunkPosition(pos))
+				}
+				d.SetBlockAt(Block{Position: pos, Type: t})
+			}
+		}
+	}
+}
@@ -24,5 +24,6 @@ func main() {
 	} else {
 		server.Start("localhost:53785")
 		client.Start()
+		server.SaveWorld()
 	}
 }
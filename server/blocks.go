@@ -0,0 +1,28 @@
+package server
+
+import (
+	"math/rand"
+	"remakemc/config"
+	"remakemc/core"
+)
+
+var blockRand = rand.New(rand.NewSource(RAND_SEED))
+
+// Runs the scheduled and random ticks of blocks
+// You must lock Dim yourself
+func tickBlocks() {
+	ctx := &core.BlockTickContext{
+		Dim:      Dim,
+		Rand:     blockRand,
+		SetBlock: setBlock,
+	}
+	core.BlockTickSystem(ctx, config.App.Server.RandomTickSpeed)
+}
+
+// Changes the block, updating clients and the blocks around it
+// You must lock Dim yourself
+func setBlock(b core.Block) {
+	Dim.SetBlockAt(b)
+	broadcastBlockUpdate(b)
+	Dim.ScheduleUpdateTicks(b.Position)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"remakemc/config"
+	"remakemc/core"
+	"remakemc/core/proto"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// Extra reach allowed when validating attacks, for latency
+const ATTACK_REACH_LEEWAY = 1
+
+func (c *Client) HandleEntityAttack(a proto.EntityAttack) {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	if !c.canInteract() || time.Since(c.lastAttack) < time.Duration(core.ATTACK_COOLDOWN*0.8*float64(time.Second)) {
+		// TODO Invalid
+		return
+	}
+
+	eye := c.Position.Position.Add(core.PLAYER_EYE_OFFSET)
+	selectedSlot := c.Inventory.GetSlots()[c.HotbarSlotSelected]
+	damage := core.AttackDamage(selectedSlot.GetStack())
+
+	// Find the target, which may be a player or an entity
+	var target mgl32.Vec3
+	if v := getClient(uuid.UUID(a)); v != nil {
+		if v == c || !config.App.Server.PvP || v.Health.Dead() {
+			return
+		}
+
+		// Check the reach to where the attacker saw the target
+		var aabb mgl32.Vec3
+		target, aabb = c.rewind(v.Position.EntityID, v.Position.Position, v.Position.AABB)
+		if !canReach(eye, target, aabb) {
+			return
+		}
+
+		if v.GameMode.TakesDamage() {
+			v.damage(damage)
+			v.knockback(target.Sub(c.Position.Position))
+		}
+	} else {
+		e, ok := getEntity(uuid.UUID(a)).(interface {
+			core.Entity
+			core.PositionFace
+			core.PhysicsFace
+			core.HealthFace
+		})
+		if !ok {
+			return
+		}
+
+		var aabb mgl32.Vec3
+		target, aabb = c.rewind(e.GetID(), *e.GetPosition(), e.GetPhysicsComp().AABB)
+		if !canReach(eye, target, aabb) {
+			return
+		}
+
+		core.ApplyKnockback(e.GetPhysicsComp(), target.Sub(c.Position.Position), core.KNOCKBACK_SPEED)
+		damageEntity(e, damage)
+	}
+	c.lastAttack = time.Now()
+	sendMetadata(c.Position.EntityID, core.AnimationSwing)
+
+	// Wear out the weapon
+	if s := selectedSlot.GetStack(); c.GameMode.ConsumesItems() && !s.IsEmpty() && core.ItemRegistry[s.Item].MaxDamage > 0 {
+		s.AddDamage(1)
+		selectedSlot.SetStack(s)
+		c.sendContainerContents(c.Inventory)
+	}
+}
+
+// Whether an attacker can reach the box from their eye, without hitting
+// through walls
+// You must lock Dim yourself
+func canReach(eye, pos, aabb mgl32.Vec3) bool {
+	return core.DistanceToAABB(eye, pos, pos.Add(aabb)) <= core.ATTACK_REACH+ATTACK_REACH_LEEWAY &&
+		core.CanSeeAABB(Dim, eye, pos, pos.Add(aabb))
+}
+
+// Damages the entity, and removes it if it dies
+// You must lock Dim yourself
+func damageEntity(e interface {
+	core.Entity
+	core.HealthFace
+}, amount float32) {
+	if amount <= 0 {
+		return
+	}
+
+	if e.GetHealthComp().Damage(amount, Dim.Ticks) {
+		// TODO Drop loot
+		removeEntity(e)
+		return
+	}
+	sendMetadata(e.GetID(), core.AnimationHurt)
+}
+
+// Finds the joined client playing as the entity with the ID
+// You must lock Dim yourself
+func getClient(entityID uuid.UUID) *Client {
+	for _, v := range clients {
+		if v.joined && v.Position.EntityID == entityID {
+			return v
+		}
+	}
+	return nil
+}
+
+// You must lock Dim yourself
+func getEntity(entityID uuid.UUID) core.Entity {
+	return Dim.Entities.Get(entityID)
+}
+
+// Removes the entity from the dimension, and from all clients
+// You must lock Dim yourself
+func removeEntity(e core.Entity) {
+	if !Dim.Entities.Remove(e.GetID()) {
+		// Already removed, such as when killed twice in a tick
+		return
+	}
+
+	delete(sentEntityPositions, e.GetID())
+	positionHistory.Forget(e.GetID())
+	if m, ok := e.(core.Mob); ok {
+		m.GetAIComp().StopMoving()
+		delete(naturalMobs, m.GetID())
+	}
+
+	for _, v := range clients {
+		if v.joined {
+			v.SendQueue <- proto.ENTITY_DELETE
+			v.SendQueue <- proto.EntityDelete(e.GetID())
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"remakemc/core"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Reads commands from the server's standard input
+func runConsole() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+
+		switch args[0] {
+		case "gamemode":
+			if len(args) != 3 {
+				fmt.Println("usage: gamemode <username> <survival|creative|spectator>")
+				continue
+			}
+
+			m, ok := core.ParseGameMode(args[2])
+			if !ok {
+				fmt.Println("unknown game mode", args[2])
+				continue
+			}
+
+			Dim.Lock.Lock()
+			var found bool
+			for _, v := range clients {
+				if v.joined && v.Username == args[1] {
+					v.setGameMode(m)
+					found = true
+				}
+			}
+			Dim.Lock.Unlock()
+
+			if !found {
+				fmt.Println("no player named", args[1])
+			} else {
+				fmt.Println("set", args[1], "to", m)
+			}
+		case "summon":
+			if len(args) != 3 {
+				fmt.Println("usage: summon <type> <username>")
+				continue
+			}
+
+			// Spawn in front of the player
+			Dim.Lock.Lock()
+			var found bool
+			for _, v := range clients {
+				if v.joined && v.Username == args[2] {
+					found = true
+					pos := v.Position.Position.Add(mgl32.Vec3{
+						float32(math.Sin(v.Position.LookAzimuth)) * 2,
+						0,
+						float32(math.Cos(v.Position.LookAzimuth)) * 2,
+					})
+
+					if m := newMob(args[1], pos); m != nil {
+						spawnEntity(m)
+						fmt.Println("summoned", args[1], "at", pos)
+					} else {
+						fmt.Println("unknown mob", args[1])
+					}
+					break
+				}
+			}
+			Dim.Lock.Unlock()
+
+			if !found {
+				fmt.Println("no player named", args[2])
+			}
+		case "save":
+			SaveWorld()
+		default:
+			fmt.Println("unknown command", args[0])
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"remakemc/core"
+	"remakemc/core/container"
+	"remakemc/core/proto"
+
+	"github.com/google/uuid"
+)
+
+// A ContainerSession tracks the clients viewing a shared container, so that
+// all of them can be updated whenever any of them changes it.
+type ContainerSession struct {
+	Container core.SharedContainer
+	Viewers   []*Client
+}
+
+// Open container sessions, addressed by the entity ID of the container.
+// You must lock Dim to access this
+var containerSessions = make(map[uuid.UUID]*ContainerSession)
+
+// The creative palette, shared by every client in creative.
+// You must lock Dim to access this
+var creativePalette *container.Creative
+
+// Returns the container the client sees when interacting with the container
+// with the entity ID, or nil if they are not viewing it.
+// You must lock Dim yourself
+func (c *Client) getContainerView(entityID uuid.UUID) core.Container {
+	if entityID == c.Position.EntityID {
+		return c.Inventory
+	}
+
+	if entityID == container.CreativeID && c.GameMode == core.Creative {
+		// The palette has no session, as it never changes
+		if creativePalette == nil {
+			creativePalette = new(container.Creative)
+			creativePalette.Init(false, container.CreativeID)
+		}
+		return creativePalette.ViewWith(c.Inventory)
+	}
+
+	if c.OpenContainer == nil || c.OpenContainer.Container.GetEntityID() != entityID {
+		return nil
+	}
+
+	return c.OpenContainer.Container.ViewWith(c.Inventory)
+}
+
+// Opens the container for the client, closing any other container they had open.
+// You must lock Dim yourself
+func (c *Client) openContainer(entity core.Entity, container core.SharedContainer) {
+	if c.OpenContainer != nil {
+		c.closeContainer()
+	}
+
+	s, ok := containerSessions[entity.GetID()]
+	if !ok {
+		s = &ContainerSession{Container: container}
+		containerSessions[entity.GetID()] = s
+	}
+	s.Viewers = append(s.Viewers, c)
+	c.OpenContainer = s
+
+	view := container.ViewWith(c.Inventory)
+	c.containerRevision++
+	c.SendQueue <- proto.CONTAINER_OPEN
+	c.SendQueue <- proto.ContainerOpen{
+		ContainerContents: proto.ContainerContents{
+			EntityID:      entity.GetID(),
+			Slots:         core.GetStacksFromSlots(view.GetSlots()),
+			FloatingStack: view.GetFloating(),
+			Revision:      c.containerRevision,
+		},
+		ContainerType: entity.GetTypeName(),
+	}
+}
+
+// Removes the client from the viewers of their open container. Any floating stack
+// is returned to their inventory.
+// You must lock Dim yourself
+func (c *Client) closeContainer() {
+	s := c.OpenContainer
+	if s == nil {
+		return
+	}
+	c.OpenContainer = nil
+
+	for k, v := range s.Viewers {
+		if v == c {
+			s.Viewers = append(s.Viewers[:k], s.Viewers[k+1:]...)
+			break
+		}
+	}
+	if len(s.Viewers) == 0 {
+		delete(containerSessions, s.Container.GetEntityID())
+	}
+
+	c.returnFloating()
+}
+
+// Forcibly closes the container for all of its viewers, such as when the block
+// holding it has been broken.
+// You must lock Dim yourself
+func closeContainerSession(entityID uuid.UUID) {
+	s, ok := containerSessions[entityID]
+	if !ok {
+		return
+	}
+
+	for _, v := range s.Viewers {
+		v.OpenContainer = nil
+		v.returnFloating()
+
+		v.SendQueue <- proto.CONTAINER_CLOSE
+		v.SendQueue <- proto.ContainerClose(entityID)
+	}
+	delete(containerSessions, entityID)
+}
+
+// Puts the floating stack back into the client's inventory, and updates them
+func (c *Client) returnFloating() {
+	c.giveStack(c.Inventory.GetFloating())
+	c.Inventory.SetFloating(core.ItemStack{})
+
+	c.sendContainerContents(c.Inventory)
+}
+
+// Puts the stack into the client's inventory, without updating them
+// You must lock Dim yourself
+func (c *Client) giveStack(s core.ItemStack) {
+	for _, v := range c.Inventory.GetSlots() {
+		if s.IsEmpty() {
+			break
+		}
+
+		if v.GetStack().IsEmpty() || v.GetStack().CanStackWith(s) {
+			s = v.PutStack(s)
+		}
+	}
+	// TODO Drop any items that didn't fit
+}
+
+// Removes one of the item from the client's inventory, without updating them.
+// Returns false if they don't have any.
+// You must lock Dim yourself
+func (c *Client) takeItem(item string) bool {
+	for _, v := range c.Inventory.GetSlots() {
+		s := v.GetStack()
+		if s.IsEmpty() || s.Item != item {
+			continue
+		}
+
+		s.Count--
+		if s.Count == 0 {
+			s = core.ItemStack{}
+		}
+		v.SetStack(s)
+		return true
+	}
+	return false
+}
+
+// Changes the client's game mode and tells them about it
+// You must lock Dim yourself
+func (c *Client) setGameMode(m core.GameMode) {
+	if c.OpenContainer == nil {
+		c.returnFloating()
+	}
+	c.GameMode = m
+	core.SetPlayerGameMode(c.body, m)
+
+	c.SendQueue <- proto.PLAYER_GAME_MODE
+	c.SendQueue <- proto.PlayerGameMode(m)
+}
+
+// Sends the contents of the container to the client, as a new revision
+func (c *Client) sendContainerContents(container core.Container) {
+	c.containerRevision++
+
+	c.SendQueue <- proto.CONTAINER_CONTENTS
+	c.SendQueue <- proto.ContainerContents{
+		EntityID:      container.GetEntityID(),
+		Slots:         core.GetStacksFromSlots(container.GetSlots()),
+		FloatingStack: container.GetFloating(),
+		Revision:      c.containerRevision,
+	}
+}
+
+// Sends the contents of the container to everyone viewing it, except the client given
+// You must lock Dim yourself
+func (s *ContainerSession) broadcastContents(except *Client) {
+	for _, v := range s.Viewers {
+		if v != except {
+			v.sendContainerContents(s.Container.ViewWith(v.Inventory))
+		}
+	}
+}
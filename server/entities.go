@@ -0,0 +1,110 @@
+package server
+
+import (
+	"log"
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// An entity which moves around, and is shown to clients
+type movingEntity interface {
+	core.Entity
+	core.PositionFace
+	core.PhysicsFace
+	core.LookFace
+}
+
+// The positions of entities last sent to clients, to only send changes
+// You must lock Dim to access this
+var sentEntityPositions = make(map[uuid.UUID]sentPosition)
+
+type sentPosition struct {
+	Position mgl32.Vec3
+	// Whether the position has been sent twice. Clients carry on moving
+	// entities after their last position, so must be told when they stop.
+	Stopped bool
+}
+
+// Moves all entities for a tick, and sends clients any changes in their positions
+// You must lock Dim yourself
+func tickEntities() {
+	tickMobs()
+	// Projectiles must find what they hit before they are moved
+	tickProjectiles()
+	core.PhysicsSystem(Dim)
+	tickMobFalls()
+
+	now := serverTime()
+	for _, e := range core.Query[movingEntity](&Dim.Entities) {
+		positionHistory.Record(e.GetID(), now, *e.GetPosition(), e.GetPhysicsComp().AABB)
+
+		sent, ok := sentEntityPositions[e.GetID()]
+		unchanged := ok && sent.Position == *e.GetPosition()
+		if unchanged && sent.Stopped {
+			continue
+		}
+		sentEntityPositions[e.GetID()] = sentPosition{Position: *e.GetPosition(), Stopped: unchanged}
+
+		for _, v := range clients {
+			if v.joined {
+				v.SendQueue <- proto.ENTITY_POSITION
+				v.SendQueue <- entityPositionMessage(e)
+			}
+		}
+	}
+}
+
+// Adds the entity to the dimension, and shows it to all clients
+// You must lock Dim yourself
+func spawnEntity(e movingEntity) {
+	Dim.Entities.Add(e)
+
+	for _, v := range clients {
+		if v.joined {
+			v.SendQueue <- proto.ENTITY_CREATE
+			v.SendQueue <- entityCreateMessage(e)
+		}
+	}
+}
+
+// Creates a new entity of the registered type, or returns nil if the type
+// doesn't exist
+func newEntity(typeName string) core.Entity {
+	t := core.EntityRegistry[typeName]
+	if t == nil {
+		return nil
+	}
+	return t.New(uuid.New())
+}
+
+func entityCreateMessage(e movingEntity) proto.EntityCreate {
+	msg := proto.EntityCreate{
+		EntityPosition: entityPositionMessage(e),
+		EntityType:     e.GetTypeName(),
+	}
+
+	if t := core.EntityRegistry[e.GetTypeName()]; t != nil && t.EncodeSpawn != nil {
+		data, err := t.EncodeSpawn(e)
+		if err != nil {
+			log.Println("Error encoding spawn data of", e.GetTypeName(), err)
+		} else {
+			msg.SpawnData = data
+		}
+	}
+	return msg
+}
+
+func entityPositionMessage(e movingEntity) proto.EntityPosition {
+	return proto.EntityPosition{
+		EntityID:      e.GetID(),
+		Position:      *e.GetPosition(),
+		Yaw:           e.GetLookComp().Yaw,
+		AABB:          e.GetPhysicsComp().AABB,
+		LookAzimuth:   e.GetLookComp().Azimuth,
+		LookElevation: e.GetLookComp().Elevation,
+		Time:          serverTime(),
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import "remakemc/core"
+
+// Uses a bucket on the block, picking up the fluid source or placing the
+// bucket's fluid. Returns false if the held item isn't a bucket.
+// You must lock Dim yourself
+func (c *Client) useBucket(target core.Block, face core.BlockFace) bool {
+	selectedSlot := c.Inventory.GetSlots()[c.HotbarSlotSelected]
+	s := selectedSlot.GetStack()
+	item := core.ItemRegistry[s.Item]
+
+	var newBlock core.Block
+	var emptied string
+	switch {
+	case item.PicksUpFluid:
+		if !target.Type.IsFluidSource() || target.Type.Fluid.Bucket == "" {
+			return true
+		}
+		newBlock = core.Block{Position: target.Position}
+		emptied = target.Type.Fluid.Bucket
+
+	case item.PlacesFluid != "":
+		f := core.FluidRegistry[item.PlacesFluid]
+		newBlock = core.Block{Position: target.Position, Type: f.Source()}
+		if target.Type.Fluid == nil {
+			newBlock.Position = target.Position.Add(core.FaceDirection[face])
+			if t := Dim.GetBlockAt(newBlock.Position).Type; t != nil && t.Fluid == nil {
+				return true
+			}
+		}
+		emptied = item.EmptiesTo
+
+	default:
+		return false
+	}
+
+	// TODO Check whether the player is inside the fluid
+	setBlock(newBlock)
+
+	if c.GameMode.ConsumesItems() {
+		// Swap one bucket for the other
+		if s.Count == 1 {
+			selectedSlot.SetStack(core.ItemStack{Item: emptied, Count: 1})
+		} else {
+			s.Count--
+			selectedSlot.SetStack(s)
+			c.giveStack(core.ItemStack{Item: emptied, Count: 1})
+		}
+		c.sendContainerContents(c.Inventory)
+	}
+
+	return true
+}
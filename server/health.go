@@ -0,0 +1,103 @@
+package server
+
+import (
+	"fmt"
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Where players join and respawn
+var SpawnPoint = mgl32.Vec3{0, 95, 0}
+
+// Damages the player for landing after a fall, at the speed their body landed
+// at when the server moved it
+// You must lock Dim yourself
+func (c *Client) checkFall() {
+	landing := c.body.TakeLandingSpeed()
+	if c.GameMode.TakesDamage() {
+		c.damage(core.FallDamage(landing))
+	}
+}
+
+// Damages the player every VOID_DAMAGE_INTERVAL ticks they are in the void
+// You must lock Dim yourself
+func (c *Client) tickVoid() {
+	if !core.InVoid(c.Position.Position) || c.GameMode == core.Spectator || c.Health.Dead() {
+		c.voidTicks = 0
+		return
+	}
+
+	if c.voidTicks%core.VOID_DAMAGE_INTERVAL == 0 {
+		c.damage(core.VOID_DAMAGE)
+	}
+	c.voidTicks++
+}
+
+func (c *Client) HandlePlayerRespawn() {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	if !c.Health.Dead() {
+		// TODO Invalid
+		return
+	}
+
+	c.Health.Reset()
+	c.Hunger = core.NewHungerComp()
+	c.teleport(SpawnPoint)
+	// Attacks can't be rewound to before the player respawned
+	positionHistory.Forget(c.Position.EntityID)
+
+	c.SendQueue <- proto.PLAYER_RESPAWN
+	c.SendQueue <- proto.PlayerRespawn{
+		Position: SpawnPoint,
+		Health:   c.Health.Health,
+	}
+	c.sendHunger()
+}
+
+// Damages the player and tells them their new health
+// You must lock Dim yourself
+func (c *Client) damage(amount float32) {
+	if amount <= 0 || c.Health.Dead() {
+		return
+	}
+
+	died := c.Health.Damage(amount, Dim.Ticks)
+	c.sendHealth()
+	sendMetadata(c.Position.EntityID, core.AnimationHurt)
+
+	if died {
+		c.die()
+	}
+}
+
+// Tells the client their health
+// You must lock Dim yourself
+func (c *Client) sendHealth() {
+	c.SendQueue <- proto.PLAYER_HEALTH
+	c.SendQueue <- proto.PlayerHealth(c.Health.Health)
+}
+
+// Closes the player's container and empties their inventory. The client
+// shows the respawn screen once it sees it has no health.
+// You must lock Dim yourself
+func (c *Client) die() {
+	fmt.Println(c.Username, "died")
+
+	c.closeContainer()
+
+	for _, v := range c.Inventory.GetSlots() {
+		v.SetStack(core.ItemStack{})
+	}
+	c.Inventory.SetFloating(core.ItemStack{})
+	c.sendContainerContents(c.Inventory)
+}
+
+// Whether the player can currently dig, place, and use containers
+// You must lock Dim yourself
+func (c *Client) canInteract() bool {
+	return c.GameMode.CanInteract() && !c.Health.Dead()
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"remakemc/core"
+	"remakemc/core/proto"
+	"time"
+)
+
+// Drains the hunger for a jump
+// You must lock Dim yourself
+func (c *Client) exhaustJump() {
+	if !c.GameMode.TakesDamage() {
+		return
+	}
+
+	if c.body.Sprinting {
+		c.Hunger.Exhaust(core.EXHAUSTION_SPRINT_JUMP)
+	} else {
+		c.Hunger.Exhaust(core.EXHAUSTION_JUMP)
+	}
+}
+
+func (c *Client) HandleItemUse(u proto.ItemUse) {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	if !c.canInteract() {
+		return
+	}
+
+	selectedSlot := c.Inventory.GetSlots()[c.HotbarSlotSelected]
+	s := selectedSlot.GetStack()
+	if s.IsEmpty() || core.ItemRegistry[s.Item].UseDuration == 0 {
+		// TODO Invalid
+		return
+	}
+	item := core.ItemRegistry[s.Item]
+
+	if !u.FinishUsing {
+		c.useStarted = time.Now()
+		return
+	}
+
+	// Using must have been started long enough ago, allowing for latency
+	useTime := time.Second / TICK_RATE * time.Duration(item.UseDuration) * 4 / 5
+	if c.useStarted.IsZero() || time.Since(c.useStarted) < useTime {
+		// TODO Invalid, the client should be corrected
+		return
+	}
+	c.useStarted = time.Time{}
+
+	if item.Food != nil {
+		if !c.Hunger.CanEat() && c.GameMode.TakesDamage() {
+			return
+		}
+		c.Hunger.Eat(item.Food)
+	}
+
+	if item.Shoots != "" && !c.shoot(item) {
+		return
+	}
+
+	if c.GameMode.ConsumesItems() {
+		if item.Ammo != "" {
+			// The ammo was used up instead, so wear out the item
+			s.AddDamage(1)
+		} else {
+			s.Count--
+			if s.Count == 0 {
+				s = core.ItemStack{}
+			}
+		}
+		selectedSlot.SetStack(s)
+		c.sendContainerContents(c.Inventory)
+	}
+	c.sendHunger()
+}
+
+// Drains the hunger, and regenerates or starves the player
+// You must lock Dim yourself
+func (c *Client) tickHunger() {
+	if !c.GameMode.TakesDamage() || c.Health.Dead() {
+		return
+	}
+
+	// Sprinting stops once there isn't enough food, with the next input
+	if c.body.Sprinting {
+		c.Hunger.Exhaust(core.EXHAUSTION_SPRINTING)
+	}
+
+	heal, starve := c.Hunger.Tick(c.Health.Health < c.Health.MaxHealth)
+	if heal > 0 {
+		c.Health.Heal(heal)
+		c.sendHealth()
+	}
+	// Starving stops at half a heart
+	if starve > 0 && c.Health.Health > 1 {
+		c.damage(starve)
+	}
+
+	if c.Hunger.Food != c.sentHunger.Food || c.Hunger.Saturation != c.sentHunger.Saturation {
+		c.sendHunger()
+	}
+}
+
+// Tells the client their food and saturation levels
+// You must lock Dim yourself
+func (c *Client) sendHunger() {
+	c.sentHunger = proto.PlayerHunger{
+		Food:       c.Hunger.Food,
+		Saturation: c.Hunger.Saturation,
+	}
+
+	c.SendQueue <- proto.PLAYER_HUNGER
+	c.SendQueue <- c.sentHunger
+}
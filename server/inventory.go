@@ -1,87 +1,81 @@
 package server
 
-// func (c *Client) HandlePlayerHeldItem(h proto.PlayerHeldItem) {
-// 	if h < 0 || h > 8 {
-// 		// TODO Invalid
-// 		return
-// 	}
-
-// 	c.HotbarSlotSelected = int(h)
-
-// 	// Update other clients about the client's new held item
-// 	for _, v := range clients {
-// 		if v != c {
-// 			v.SendQueue <- proto.ENTITY_EQUIPMENT
-// 			v.SendQueue <- proto.EntityEquipment{
-// 				EntityID: c.Position.EntityID,
-// 				EntityEquipment: core.EntityEquipment{
-// 					HeldItemType: c.Inventory.Slots[h].GetStack().Item,
-// 				},
-// 			}
-// 		}
-// 	}
-// }
-
-// func (c *Client) HandleContainerClick(m proto.ContainerClick) {
-// 	// TODO Update only clients who are looking at this container
-
-// 	// TODO Add support for non-inventories (proto level)
-// 	var i core.Container
-// 	if m.EntityID == c.Position.EntityID {
-// 		i = c.Inventory
-// 	} else {
-// 		Dim.Lock.Lock()
-// 		for _, v := range Dim.Entities {
-// 			if v.ID == m.EntityID {
-// 				i = v.Inventory
-// 				break
-// 			}
-// 		}
-// 		Dim.Lock.Unlock()
-// 	}
-
-// 	hovered := i.GetSlots()[m.SlotIndex]
-
-// 	if m.LeftClick {
-// 		if i.GetFloating().IsEmpty() && !hovered.GetStack().IsEmpty() {
-// 			// Take the stack from the slot
-// 			s, ok := hovered.TakeStack(false)
-// 			if ok {
-// 				i.SetFloating(s)
-// 			}
-// 		} else if !i.GetFloating().IsEmpty() {
-// 			// Place the stack in the slot
-// 			i.SetFloating(hovered.PutStack(i.GetFloating()))
-// 		}
-
-// 	} else if m.RightClick {
-// 		if i.GetFloating().IsEmpty() && !hovered.GetStack().IsEmpty() {
-// 			// Take half the stack from the slot
-// 			s, ok := hovered.TakeStack(true)
-// 			if ok {
-// 				i.SetFloating(s)
-// 			}
-
-// 		} else if !i.GetFloating().IsEmpty() {
-// 			if hovered.GetStack().Item == i.GetFloating().Item || hovered.GetStack().IsEmpty() {
-// 				// Place one item in the slot
-// 				m := hovered.PutStack(core.ItemStack{Item: i.GetFloating().Item, Count: 1})
-// 				if m.IsEmpty() {
-// 					f := i.GetFloating()
-// 					f.Count--
-// 					i.SetFloating(f)
-// 				}
-// 			} else {
-// 				// Exchange items
-// 				i.SetFloating(hovered.PutStack(i.GetFloating()))
-// 			}
-// 		}
-// 	}
-
-// 	c.SendQueue <- proto.CONTAINER_CONTENTS
-// 	c.SendQueue <- proto.ContainerContents{
-// 		EntityID:      m.EntityID,
-// 		Slots:         core.GetStacksFromSlots(i.GetSlots()),
-// 		FloatingStack: i.GetFloating(),
-// 	}
-// }
+import (
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) HandlePlayerHeldItem(h proto.PlayerHeldItem) {
+	if h < 0 || h > 8 {
+		// TODO Invalid
+		return
+	}
+
+	Dim.Lock.Lock()
+	c.HotbarSlotSelected = int(h)
+	Dim.Lock.Unlock()
+}
+
+func (c *Client) HandleContainerClick(m proto.ContainerClick) {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	i := c.getContainerView(m.EntityID)
+	if i == nil || !c.canInteract() || m.SlotIndex < 0 || m.SlotIndex >= len(i.GetSlots()) || m.Revision != c.containerRevision {
+		// The client has diverged from us, so correct them
+		c.resyncContainer(i)
+		return
+	}
+
+	m.Apply(i)
+	c.acceptContainerChange(m.EntityID)
+}
+
+func (c *Client) HandleContainerDrag(m proto.ContainerDrag) {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	i := c.getContainerView(m.EntityID)
+	if i == nil || !c.canInteract() || m.Revision != c.containerRevision {
+		c.resyncContainer(i)
+		return
+	}
+
+	m.Apply(i)
+	c.acceptContainerChange(m.EntityID)
+}
+
+// Accept a change the client made to a container. The client has already
+// predicted it, so only the other viewers of the container need updating.
+// You must lock Dim yourself
+func (c *Client) acceptContainerChange(entityID uuid.UUID) {
+	c.containerRevision++
+
+	if entityID != c.Position.EntityID && c.OpenContainer != nil {
+		c.OpenContainer.broadcastContents(c)
+	}
+}
+
+// Sends the full contents of the container to the client, or of their inventory
+// if the container is nil
+func (c *Client) resyncContainer(container core.Container) {
+	if container == nil {
+		container = c.Inventory
+	}
+	c.sendContainerContents(container)
+}
+
+func (c *Client) HandleContainerClose(m proto.ContainerClose) {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	if c.OpenContainer == nil || c.OpenContainer.Container.GetEntityID() != uuid.UUID(m) {
+		// The inventory has no session, but may still have a floating stack
+		c.returnFloating()
+		return
+	}
+
+	c.closeContainer()
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"remakemc/core"
+	"remakemc/core/proto"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// The number of ticks between pings of each client, to measure their round
+// trip time
+const PING_INTERVAL = TICK_RATE
+
+// Where players and entities were recently, to check hits against where
+// attackers saw their targets
+// You must lock Dim to access this
+var positionHistory core.PositionHistory
+
+// Pings the client every PING_INTERVAL
+// You must lock Dim yourself
+func (c *Client) tickPing() {
+	c.pingTicks++
+	if c.pingTicks%PING_INTERVAL != 0 {
+		return
+	}
+
+	c.SendQueue <- proto.PING
+	c.SendQueue <- proto.Ping(serverTime())
+}
+
+func (c *Client) HandlePong(p proto.Pong) {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	rtt := serverTime() - time.Duration(p)
+	if rtt < 0 {
+		// TODO Invalid
+		return
+	}
+
+	// Smooth out the jitter of single pings
+	if c.rtt == 0 {
+		c.rtt = rtt
+	} else {
+		c.rtt += (rtt - c.rtt) / 8
+	}
+}
+
+// Returns where the client saw the player or entity with the ID when they
+// acted, or the current position and box if its history is unknown
+// You must lock Dim yourself
+func (c *Client) rewind(id uuid.UUID, pos, aabb mgl32.Vec3) (mgl32.Vec3, mgl32.Vec3) {
+	if p, a, ok := positionHistory.Rewind(id, core.ViewTime(serverTime(), c.rtt)); ok {
+		return p, a
+	}
+	return pos, aabb
+}
+
+// Rewinds the targets of projectiles to where their owner saw them, if the
+// owner is a player
+// You must lock Dim yourself
+func rewindProjectileTarget(p core.Projectile, t core.ProjectileTarget) core.ProjectileTarget {
+	if c := getClient(p.GetProjectileComp().Owner); c != nil {
+		t.Position, t.AABB = c.rewind(t.ID, t.Position, t.AABB)
+	}
+	return t
+}
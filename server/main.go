@@ -3,8 +3,11 @@ package server
 import (
 	"fmt"
 	"net"
+	"os"
+	"os/signal"
 	"remakemc/core"
 	"remakemc/core/container"
+	_ "remakemc/core/entities"
 	"remakemc/core/proto"
 	"time"
 
@@ -20,12 +23,56 @@ type Client struct {
 	Position    proto.PlayerPosition
 	OldPosition proto.PlayerPosition
 
+	// Set once the client has joined. You must lock Dim to access this
+	joined bool
+
+	GameMode core.GameMode
+	Health   core.HealthComp
+	Hunger   core.HungerComp
+
+	// The hunger the client was last told about
+	sentHunger proto.PlayerHunger
+
+	// Moved by the client's inputs, to check their position
+	// You must lock Dim to access this
+	body             *playerBody
+	pendingKnockback []proto.PlayerKnockback
+
+	// What other clients were last told they can see of the client
+	// You must lock Dim to access this
+	sentMetadata core.EntityMetadata
+
+	// When the client last attacked, to limit the rate of attacks
+	lastAttack time.Time
+
+	// When the client started using their held item, such as eating
+	useStarted time.Time
+
+	// The ticks the client has been in the void
+	// You must lock Dim to access this
+	voidTicks int
+
+	// The block being dug in survival, and when digging started
+	digPosition core.Vec3
+	digStarted  time.Time
+
 	HotbarSlotSelected int
 	Inventory          *container.Inventory
+	OpenContainer      *ContainerSession
+
+	// Incremented with every change to the client's containers. See proto.ContainerClick
+	// You must lock Dim to access this
+	containerRevision int
 
 	loadedChunks []core.Vec3
+
+	// The smoothed round trip time of pings, for lag compensation
+	// You must lock Dim to access this
+	rtt       time.Duration
+	pingTicks int
 }
 
+// You must lock Dim to access this
 var clients []*Client
 
 func (c *Client) Listen() {
@@ -47,79 +94,98 @@ func (c *Client) Listen() {
 
 			c.HandleJoin(j)
 
-		case proto.PLAYER_JUMP:
-			fmt.Println(c.Username, "jumped")
+		case proto.PLAYER_RESPAWN:
+			c.HandlePlayerRespawn()
 
-		case proto.PLAYER_SNEAKING:
-			var s proto.PlayerSneaking
-			err := d.Decode(&s)
+		case proto.PONG:
+			var p proto.Pong
+			err := d.Decode(&p)
 			if err != nil {
 				panic(err)
 			}
 
-			if s {
-				fmt.Println(c.Username, "started sneaking")
-			} else {
-				fmt.Println(c.Username, "stopped sneaking")
+			c.HandlePong(p)
+
+		case proto.ITEM_USE:
+			var u proto.ItemUse
+			err := d.Decode(&u)
+			if err != nil {
+				panic(err)
 			}
 
-		case proto.PLAYER_SPRINTING:
-			var s proto.PlayerSprinting
-			err := d.Decode(&s)
+			c.HandleItemUse(u)
+
+		case proto.PLAYER_POSITION:
+			var m proto.PlayerMove
+			err := d.Decode(&m)
 			if err != nil {
 				panic(err)
 			}
 
-			if s {
-				fmt.Println(c.Username, "started sprinting")
-			} else {
-				fmt.Println(c.Username, "stopped sprinting")
+			c.HandlePlayerPosition(m)
+
+		case proto.ENTITY_ATTACK:
+			var a proto.EntityAttack
+			err := d.Decode(&a)
+			if err != nil {
+				panic(err)
 			}
 
-		// case proto.PLAYER_POSITION:
-		// 	var p proto.PlayerPosition
-		// 	err := d.Decode(&p)
-		// 	if err != nil {
-		// 		panic(err)
-		// 	}
+			c.HandleEntityAttack(a)
 
-		// 	c.HandlePlayerPosition(p)
+		case proto.BLOCK_DIG:
+			var b proto.BlockDig
+			err := d.Decode(&b)
+			if err != nil {
+				panic(err)
+			}
 
-		// case proto.BLOCK_DIG:
-		// 	var b proto.BlockDig
-		// 	err := d.Decode(&b)
-		// 	if err != nil {
-		// 		panic(err)
-		// 	}
+			c.HandleBlockDig(b)
 
-		// 	c.HandleBlockDig(b)
+		case proto.BLOCK_INTERACTION:
+			var b proto.BlockInteraction
+			err := d.Decode(&b)
+			if err != nil {
+				panic(err)
+			}
 
-		// case proto.BLOCK_INTERACTION:
-		// 	var b proto.BlockInteraction
-		// 	err := d.Decode(&b)
-		// 	if err != nil {
-		// 		panic(err)
-		// 	}
+			c.HandleBlockInteraction(b)
 
-		// 	c.HandleBlockInteraction(b)
+		case proto.PLAYER_HELD_ITEM:
+			var h proto.PlayerHeldItem
+			err := d.Decode(&h)
+			if err != nil {
+				panic(err)
+			}
 
-		// case proto.PLAYER_HELD_ITEM:
-		// 	var h proto.PlayerHeldItem
-		// 	err := d.Decode(&h)
-		// 	if err != nil {
-		// 		panic(err)
-		// 	}
+			c.HandlePlayerHeldItem(h)
 
-		// 	c.HandlePlayerHeldItem(h)
+		case proto.CONTAINER_CLICK:
+			var m proto.ContainerClick
+			err := d.Decode(&m)
+			if err != nil {
+				panic(err)
+			}
+
+			c.HandleContainerClick(m)
+
+		case proto.CONTAINER_CLOSE:
+			var m proto.ContainerClose
+			err := d.Decode(&m)
+			if err != nil {
+				panic(err)
+			}
 
-		// case proto.CONTAINER_CLICK:
-		// 	var m proto.ContainerClick
-		// 	err := d.Decode(&m)
-		// 	if err != nil {
-		// 		panic(err)
-		// 	}
+			c.HandleContainerClose(m)
 
-		// 	c.HandleContainerClick(m)
+		case proto.CONTAINER_DRAG:
+			var m proto.ContainerDrag
+			err := d.Decode(&m)
+			if err != nil {
+				panic(err)
+			}
+
+			c.HandleContainerDrag(m)
 
 		default:
 			fmt.Println("unrecognized message from client, disconnecting them")
@@ -130,12 +196,38 @@ func (c *Client) Listen() {
 	}
 }
 
+// Moves messages from the SendQueue to a backlog, which they are written to
+// the client from. Messages are sent while Dim is locked, so one slow client
+// mustn't block the sending goroutine, and the whole server tick with it.
+// TODO Disconnect clients which fall too far behind, once disconnecting is
+// handled
 func (c *Client) Send() {
-	c.SendQueue = make(chan interface{}, 32)
-	c.encoder = msgpack.NewEncoder(c.Conn)
+	out := make(chan interface{})
+	go c.write(out)
 
+	var backlog []interface{}
 	for {
-		msg := <-c.SendQueue
+		// Only write when there is something to
+		var next chan interface{}
+		var msg interface{}
+		if len(backlog) != 0 {
+			next, msg = out, backlog[0]
+		}
+
+		select {
+		case m := <-c.SendQueue:
+			backlog = append(backlog, m)
+		case next <- msg:
+			backlog[0] = nil
+			backlog = backlog[1:]
+		}
+	}
+}
+
+// Writes the messages to the client, as fast as the connection allows
+func (c *Client) write(out <-chan interface{}) {
+	c.encoder = msgpack.NewEncoder(c.Conn)
+	for msg := range out {
 		err := c.encoder.Encode(msg)
 		if err != nil {
 			panic(err)
@@ -155,6 +247,25 @@ func Start(addr string) {
 	Dim.Lock.Unlock()
 	fmt.Println("Generated initial terrain in", time.Since(t))
 
+	Dim.Lock.Lock()
+	if err := loadWorld(); err != nil {
+		fmt.Println("Error loading world:", err)
+	}
+	fmt.Println("Loaded", Dim.Entities.Len(), "entities")
+	Dim.Lock.Unlock()
+
+	// Save before quitting with Ctrl+C
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		<-sig
+		SaveWorld()
+		os.Exit(0)
+	}()
+
+	go runConsole()
+	go tickLoop()
+
 	go func() {
 		// Start listening for connections
 		a, err := net.ResolveTCPAddr("tcp", addr)
@@ -173,8 +284,11 @@ func Start(addr string) {
 				panic(err)
 			}
 
-			c := &Client{Conn: conn}
+			// The queue is made before anything can send to it
+			c := &Client{Conn: conn, SendQueue: make(chan interface{}, 32)}
+			Dim.Lock.Lock()
 			clients = append(clients, c)
+			Dim.Lock.Unlock()
 			go c.Listen()
 			go c.Send()
 		}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// What other players can see of the client
+// You must lock Dim yourself
+func (c *Client) metadata() core.EntityMetadata {
+	return core.EntityMetadata{
+		HeldItem:  c.Inventory.GetSlots()[c.HotbarSlotSelected].GetStack().Item,
+		Sneaking:  c.body.Sneaking,
+		Sprinting: c.body.Sprinting,
+	}
+}
+
+// Tells other clients when what they can see of the client changes, such as
+// their held item
+// You must lock Dim yourself
+func (c *Client) tickMetadata() {
+	m := c.metadata()
+	if m == c.sentMetadata {
+		return
+	}
+	c.sentMetadata = m
+
+	sendMetadata(c.Position.EntityID)
+}
+
+// Returns the metadata and position of the player or entity with the ID, or
+// false if it doesn't have any metadata
+// You must lock Dim yourself
+func getMetadata(id uuid.UUID) (core.EntityMetadata, mgl32.Vec3, bool) {
+	if v := getClient(id); v != nil {
+		return v.sentMetadata, v.Position.Position, true
+	}
+
+	e, ok := getEntity(id).(interface {
+		core.PositionFace
+		core.MetadataFace
+	})
+	if !ok {
+		return core.EntityMetadata{}, mgl32.Vec3{}, false
+	}
+	return e.GetMetadataComp().Metadata, *e.GetPosition(), true
+}
+
+// Whether the client has the chunk containing the position loaded, so can see
+// what happens there
+// You must lock Dim yourself
+func (c *Client) canSee(pos mgl32.Vec3) bool {
+	chunkPos := core.ChunkPosition(core.NewVec3FromFloat(pos))
+	for _, v := range c.loadedChunks {
+		if v == chunkPos {
+			return true
+		}
+	}
+	return false
+}
+
+// Sends the metadata of the player or entity with the ID to the clients which
+// can see it, along with any animations to play
+// You must lock Dim yourself
+func sendMetadata(id uuid.UUID, animations ...core.EntityAnimation) {
+	m, pos, ok := getMetadata(id)
+	if !ok {
+		return
+	}
+
+	for _, v := range clients {
+		// Clients show their own animations
+		if !v.joined || v.Position.EntityID == id || !v.canSee(pos) {
+			continue
+		}
+
+		v.SendQueue <- proto.ENTITY_METADATA
+		v.SendQueue <- proto.EntityMetadata{
+			EntityID:   id,
+			Metadata:   m,
+			Animations: animations,
+		}
+	}
+}
+
+// Sends the metadata of the other players and entities in the chunks, which
+// the client has just loaded. Metadata in chunks which weren't loaded wasn't
+// sent to the client, so may have changed.
+// You must lock Dim yourself
+func (c *Client) sendMetadataIn(chunks []core.Vec3) {
+	loaded := make(map[core.Vec3]bool, len(chunks))
+	for _, v := range chunks {
+		loaded[v] = true
+	}
+
+	send := func(id uuid.UUID, m core.EntityMetadata, pos mgl32.Vec3) {
+		if !loaded[core.ChunkPosition(core.NewVec3FromFloat(pos))] {
+			return
+		}
+
+		c.SendQueue <- proto.ENTITY_METADATA
+		c.SendQueue <- proto.EntityMetadata{EntityID: id, Metadata: m}
+	}
+
+	for _, v := range clients {
+		if v != c && v.joined {
+			send(v.Position.EntityID, v.sentMetadata, v.Position.Position)
+		}
+	}
+	for _, v := range core.Query[interface {
+		core.Entity
+		core.PositionFace
+		core.MetadataFace
+	}](&Dim.Entities) {
+		send(v.GetID(), v.GetMetadataComp().Metadata, *v.GetPosition())
+	}
+}
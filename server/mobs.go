@@ -0,0 +1,68 @@
+package server
+
+import (
+	"math/rand"
+	"remakemc/core"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+var mobRand = rand.New(rand.NewSource(RAND_SEED))
+
+// Creates a new mob of the type, or returns nil if it isn't a mob
+func newMob(typeName string, pos mgl32.Vec3) core.Mob {
+	m, ok := newEntity(typeName).(core.Mob)
+	if !ok {
+		return nil
+	}
+	*m.GetPosition() = pos
+	return m
+}
+
+// Runs the AI of all mobs for a tick
+// You must lock Dim yourself
+func tickMobs() {
+	ctx := &core.AIContext{
+		Dim:  Dim,
+		Rand: mobRand,
+		Attack: func(m core.Mob, target uuid.UUID, damage float32) {
+			v := getClient(target)
+			if v == nil || !v.GameMode.TakesDamage() {
+				return
+			}
+
+			sendMetadata(m.GetID(), core.AnimationSwing)
+			v.damage(damage)
+			v.knockback(v.Position.Position.Sub(*m.GetPosition()))
+		},
+	}
+	for _, v := range clients {
+		if !v.joined || v.Health.Dead() || v.GameMode == core.Spectator {
+			continue
+		}
+
+		ctx.Players = append(ctx.Players, core.AITarget{
+			ID:         v.Position.EntityID,
+			Position:   v.Position.Position,
+			HeldItem:   v.Inventory.GetSlots()[v.HotbarSlotSelected].GetStack().Item,
+			Attackable: v.GameMode.TakesDamage(),
+		})
+	}
+
+	core.AISystem(ctx)
+}
+
+// Kills mobs which have fallen into the void, or onto the ground too fast.
+// Should be run after the physics.
+// You must lock Dim yourself
+func tickMobFalls() {
+	core.Each(&Dim.Entities, func(m core.Mob) {
+		// TODO Mobs walking into chunks which haven't been generated fall into the void
+		if core.InVoid(*m.GetPosition()) {
+			removeEntity(m)
+			return
+		}
+		damageEntity(m, core.FallDamage(m.GetPhysicsComp().TakeLandingSpeed()))
+	})
+}
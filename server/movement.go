@@ -0,0 +1,89 @@
+package server
+
+import (
+	"remakemc/core"
+	"remakemc/core/proto"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// How far the client can be from where the server simulates them after a
+// tick before they are corrected, to allow for rounding
+const MAX_POSITION_ERROR = 0.01
+
+// The player's body, which the server moves with the client's inputs to check
+// where the client says they are. It isn't in Dim.Entities.
+type playerBody struct {
+	core.EntityBase
+	core.PositionComp
+	core.PhysicsComp
+	core.MovementComp
+
+	hunger *core.HungerComp
+}
+
+func (b *playerBody) GetTypeName() string {
+	return "mc:player_body"
+}
+
+func (b *playerBody) GetHungerComp() *core.HungerComp {
+	return b.hunger
+}
+
+func newPlayerBody(c *Client, id uuid.UUID, pos mgl32.Vec3) *playerBody {
+	b := &playerBody{
+		EntityBase:   core.EntityBase{ID: id},
+		PositionComp: core.PositionComp{Position: pos},
+		PhysicsComp: core.PhysicsComp{
+			AABB:       core.PLAYER_AABB,
+			StepHeight: core.DEFAULT_STEP_HEIGHT,
+		},
+		MovementComp: core.NewMovementComp(),
+		hunger:       &c.Hunger,
+	}
+	core.SetPlayerGameMode(b, c.GameMode)
+	return b
+}
+
+// Runs a tick of the client's movement, the same as the client does, and
+// returns whether they jumped
+// You must lock Dim yourself
+func (c *Client) simulate(in core.PlayerInput) (jumping bool) {
+	// Knockback is applied by the client before the tick it arrives
+	for i := 0; i < in.Knockback && len(c.pendingKnockback) > 0; i++ {
+		k := c.pendingKnockback[0]
+		core.ApplyKnockback(&c.body.PhysicsComp, k.Direction, k.Speed)
+		c.pendingKnockback = c.pendingKnockback[1:]
+	}
+
+	jumping = core.ApplyPlayerInput(c.body, in)
+	core.StepBody(Dim, c.body)
+	return jumping
+}
+
+// Knocks the client away in the direction. The client moves itself, so must
+// knock itself back, and the server applies it once the client says it has.
+// TODO Apply knockback the client never says it has, such as a modified client
+// You must lock Dim yourself
+func (c *Client) knockback(dir mgl32.Vec3) {
+	k := proto.PlayerKnockback{
+		Direction: dir,
+		Speed:     core.KNOCKBACK_SPEED,
+	}
+	c.pendingKnockback = append(c.pendingKnockback, k)
+
+	c.SendQueue <- proto.PLAYER_KNOCKBACK
+	c.SendQueue <- k
+}
+
+// Moves the client's body, such as when respawning. The client is told
+// separately.
+// You must lock Dim yourself
+func (c *Client) teleport(pos mgl32.Vec3) {
+	c.Position.Position = pos
+	c.body.Position = pos
+	c.body.Velocity = mgl32.Vec3{}
+	c.body.TakeLandingSpeed()
+	c.pendingKnockback = nil
+}
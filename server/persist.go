@@ -0,0 +1,200 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"remakemc/config"
+	"remakemc/core"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// The number of ticks between saves of the world, 5 minutes
+const AUTOSAVE_INTERVAL = 5 * 60 * TICK_RATE
+
+// TODO Save the players' inventories
+const ENTITIES_FILE = "entities.msgpack"
+const WORLD_FILE = "world.msgpack"
+const CHUNKS_FILE = "chunks.msgpack"
+
+// What is saved about the dimension, other than its entities
+type savedWorld struct {
+	// Saved entities can refer to ticks, such as when they were last hurt
+	Ticks int
+}
+
+type savedEntity struct {
+	Type string
+	ID   uuid.UUID
+	// Encoded by the Save of the entity type
+	Data []byte
+	// Whether the mob spawned naturally, so can be despawned
+	Natural bool `msgpack:",omitempty"`
+}
+
+// You must lock Dim to access this
+var autosaveTicks int
+
+func worldDir() string {
+	if config.App.Server.WorldDir == "" {
+		return "world"
+	}
+	return config.App.Server.WorldDir
+}
+
+// Saves the world every AUTOSAVE_INTERVAL
+// You must lock Dim yourself
+func tickAutosave() {
+	autosaveTicks++
+	if autosaveTicks%AUTOSAVE_INTERVAL != 0 {
+		return
+	}
+
+	if err := saveWorld(); err != nil {
+		fmt.Println("Error saving world:", err)
+	}
+}
+
+// Saves the world, so it can be loaded when the server restarts
+func SaveWorld() {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	t := time.Now()
+	if err := saveWorld(); err != nil {
+		fmt.Println("Error saving world:", err)
+		return
+	}
+	fmt.Println("Saved world in", time.Since(t))
+}
+
+// Saves every entity with a type which can be saved, the chunks which have
+// changed or have ticks scheduled, and the dimension's ticks. Other chunks are
+// generated the same from the seed when the server restarts.
+// You must lock Dim yourself
+func saveWorld() error {
+	var chunks [][]byte
+	for _, c := range Dim.Chunks {
+		if !c.Changed && len(c.ScheduledTicks) == 0 {
+			continue
+		}
+
+		data, err := core.SaveChunk(c)
+		if err != nil {
+			return fmt.Errorf("saving chunk %v: %w", c.Position, err)
+		}
+		chunks = append(chunks, data)
+	}
+
+	var saved []savedEntity
+	for _, e := range core.Query[core.Entity](&Dim.Entities) {
+		t := core.EntityRegistry[e.GetTypeName()]
+		if t == nil || t.Save == nil {
+			continue
+		}
+
+		data, err := t.Save(e)
+		if err != nil {
+			return fmt.Errorf("saving %s %s: %w", e.GetTypeName(), e.GetID(), err)
+		}
+		saved = append(saved, savedEntity{
+			Type:    e.GetTypeName(),
+			ID:      e.GetID(),
+			Data:    data,
+			Natural: naturalMobs[e.GetID()],
+		})
+	}
+
+	if err := writeSaveFile(WORLD_FILE, savedWorld{Ticks: Dim.Ticks}); err != nil {
+		return err
+	}
+	if err := writeSaveFile(CHUNKS_FILE, chunks); err != nil {
+		return err
+	}
+	return writeSaveFile(ENTITIES_FILE, saved)
+}
+
+// Encodes the value to the file in the world directory
+func writeSaveFile(name string, v interface{}) error {
+	data, err := msgpack.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(worldDir(), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a crash while saving doesn't lose
+	// the last save
+	path := filepath.Join(worldDir(), name)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Decodes the file in the world directory into the value. Returns false if
+// the file hasn't been saved yet.
+func readSaveFile(name string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(worldDir(), name))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, msgpack.Unmarshal(data, v)
+}
+
+// Loads the dimension's ticks, and the chunks and entities saved by
+// saveWorld, if there are any. Saved chunks replace the generated ones.
+// You must lock Dim yourself
+func loadWorld() error {
+	var world savedWorld
+	if _, err := readSaveFile(WORLD_FILE, &world); err != nil {
+		return err
+	}
+	Dim.Ticks = world.Ticks
+
+	var chunks [][]byte
+	if _, err := readSaveFile(CHUNKS_FILE, &chunks); err != nil {
+		return err
+	}
+	for _, v := range chunks {
+		c, err := core.LoadChunk(v)
+		if err != nil {
+			fmt.Println("Error loading chunk:", err)
+			continue
+		}
+		Dim.Chunks[c.Position] = c
+	}
+
+	var saved []savedEntity
+	if ok, err := readSaveFile(ENTITIES_FILE, &saved); !ok || err != nil {
+		return err
+	}
+
+	for _, v := range saved {
+		t := core.EntityRegistry[v.Type]
+		if t == nil || t.Load == nil {
+			fmt.Println("Can't load entity of unknown type", v.Type)
+			continue
+		}
+
+		e := t.New(v.ID)
+		if err := t.Load(e, v.Data); err != nil {
+			fmt.Println("Error loading", v.Type, v.ID, err)
+			continue
+		}
+
+		Dim.Entities.Add(e)
+		if v.Natural {
+			naturalMobs[e.GetID()] = true
+		}
+	}
+	return nil
+}
@@ -5,13 +5,30 @@ import (
 	"remakemc/config"
 	"remakemc/core"
 	"remakemc/core/container"
-	"remakemc/core/items"
 	"remakemc/core/proto"
+	"time"
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/google/uuid"
 )
 
+// The fraction of a block's hardness a client must dig for before the server
+// accepts the block as dug, to allow for latency
+const DIG_TIME_LEEWAY = 0.8
+
+// How much further than BLOCK_REACH a block can be from the client, as the
+// server's position of the client lags behind
+const BLOCK_REACH_LEEWAY = 1
+
+// Whether the block is close enough to the client's eyes to dig or interact
+// with
+// You must lock Dim yourself
+func (c *Client) canReachBlock(pos core.Vec3) bool {
+	eye := c.Position.Position.Add(core.PLAYER_EYE_OFFSET)
+	min := pos.ToFloat()
+	return core.DistanceToAABB(eye, min, min.Add(mgl32.Vec3{1, 1, 1})) <= core.BLOCK_REACH+BLOCK_REACH_LEEWAY
+}
+
 func (c *Client) HandleJoin(j proto.Join) {
 	fmt.Println("join event")
 	c.Username = j.Username
@@ -20,7 +37,8 @@ func (c *Client) HandleJoin(j proto.Join) {
 	var msg proto.Play
 	msg.Player = proto.EntityPosition{
 		EntityID: uuid.New(),
-		Position: mgl32.Vec3{0, 95, 0},
+		Position: SpawnPoint,
+		AABB:     core.PLAYER_AABB,
 	}
 	c.OldPosition = proto.PlayerPosition(msg.Player)
 	c.Position = proto.PlayerPosition(msg.Player)
@@ -28,10 +46,15 @@ func (c *Client) HandleJoin(j proto.Join) {
 	// Set the inventory
 	c.Inventory = new(container.Inventory)
 	c.Inventory.Init(false, msg.Player.EntityID)
-	c.Inventory.Slots[5].SetStack(core.ItemStack{Item: items.Cobblestone.Name, Count: 64})
-	c.Inventory.Slots[6].SetStack(core.ItemStack{Item: items.Cobblestone.Name, Count: 64})
-	c.Inventory.Slots[7].SetStack(core.ItemStack{Item: items.Dirt.Name, Count: 64})
-	c.Inventory.Slots[0].SetStack(core.ItemStack{Item: items.Furnace.Name, Count: 1})
+
+	c.GameMode, _ = core.ParseGameMode(config.App.Server.DefaultGameMode)
+	msg.GameMode = c.GameMode
+
+	c.Health = core.NewHealthComp(core.PLAYER_MAX_HEALTH)
+	msg.Health = c.Health.Health
+	c.Hunger = core.NewHungerComp()
+	c.sentHunger = proto.PlayerHunger{Food: c.Hunger.Food, Saturation: c.Hunger.Saturation}
+	c.body = newPlayerBody(c, msg.Player.EntityID, msg.Player.Position)
 
 	// Determine the chunks to load
 	Dim.Lock.Lock()
@@ -60,297 +83,369 @@ func (c *Client) HandleJoin(j proto.Join) {
 	c.SendQueue <- proto.PLAY
 	c.SendQueue <- msg
 
-	// Update all clients
-	// fmt.Println("no. of clients", len(clients))
-	// for _, v := range clients {
-	// 	if v != c {
-	// 		fmt.Println("updating old client", c.Position.EntityID, "of new player", v.Position.EntityID)
-	// 		v.SendQueue <- proto.ENTITY_CREATE
-	// 		v.SendQueue <- proto.EntityCreate{
-	// 			EntityPosition: msg.Player,
-	// 			EntityType:     "mc:remoteplayer",
-	// 		}
-	// 	}
-	// }
-
-	// // Update client of all entities
-	// for _, v := range clients {
-	// 	if v != c {
-	// 		fmt.Println("updating new client of player", v.Position.EntityID)
-	// 		c.SendQueue <- proto.ENTITY_CREATE
-	// 		c.SendQueue <- proto.EntityCreate{
-	// 			EntityPosition: proto.EntityPosition(v.Position),
-	// 			EntityType:     "mc:remoteplayer",
-	// 		}
-	// 	}
-	// }
-	// for _, v := range Dim.Entities {
-	// 	c.SendQueue <- proto.ENTITY_CREATE
-	// 	c.SendQueue <- proto.EntityCreate{
-	// 		EntityPosition: proto.EntityPosition{
-	// 			EntityID:      v.ID,
-	// 			Yaw:           v.Yaw,
-	// 			Pitch:         v.Pitch,
-	// 			AABB:          v.AABB,
-	// 			LookAzimuth:   v.LookAzimuth,
-	// 			LookElevation: v.LookElevation,
-	// 		},
-	// 		EntityType: v.EntityType,
-	// 	}
-	// }
+	// Show the new player to other players, and them and the mobs to the new player
+	Dim.Lock.Lock()
+	c.joined = true
+	for _, v := range clients {
+		if v != c && v.joined {
+			v.SendQueue <- proto.ENTITY_CREATE
+			v.SendQueue <- proto.EntityCreate{
+				EntityPosition: c.entityPosition(),
+				EntityType:     "mc:remote_player",
+			}
+
+			c.SendQueue <- proto.ENTITY_CREATE
+			c.SendQueue <- proto.EntityCreate{
+				EntityPosition: v.entityPosition(),
+				EntityType:     "mc:remote_player",
+			}
+		}
+	}
+
+	for _, v := range core.Query[movingEntity](&Dim.Entities) {
+		c.SendQueue <- proto.ENTITY_CREATE
+		c.SendQueue <- entityCreateMessage(v)
+	}
+	c.sendMetadataIn(c.loadedChunks)
+	Dim.Lock.Unlock()
+}
+
+// The client's position, as it is sent to other clients
+// You must lock Dim yourself
+func (c *Client) entityPosition() proto.EntityPosition {
+	p := proto.EntityPosition(c.Position)
+	// The position may be from a while ago if the client hasn't moved, but
+	// it is still where they are
+	p.Time = serverTime()
+	return p
+}
+
+// Moves the client to where the server simulated them, after the input with
+// the sequence number. The client replays its later inputs from there.
+// You must lock Dim yourself
+func (c *Client) correctPosition(seq int) {
+	p := c.entityPosition()
+	p.Seq = seq
+	p.Velocity = c.body.Velocity
+
+	c.SendQueue <- proto.ENTITY_POSITION
+	c.SendQueue <- p
+}
+
+func (c *Client) HandlePlayerPosition(m proto.PlayerMove) {
+	// TODO Limit the inputs to one per tick, so players can't speed up by
+	// sending more
+
+	p := m.PlayerPosition
+	p.EntityID = c.OldPosition.EntityID
+	p.Time = serverTime()
+
+	Dim.Lock.Lock()
+	// Run the input the same as the client did, which collides with blocks,
+	// steps up, and stops sneaking players walking off edges
+	if c.simulate(m.Input) {
+		c.exhaustJump()
+	}
+
+	// Small differences are rounding, so the client's position is kept to
+	// stop them adding up
+	if c.body.Position.Sub(p.Position).Len() > MAX_POSITION_ERROR {
+		p.Position = c.body.Position
+	} else {
+		c.body.Position = p.Position
+	}
+	p.AABB = c.body.AABB
+
+	c.OldPosition = c.Position
+	c.Position = p
+	if p.Position != m.Position {
+		c.correctPosition(p.Seq)
+	}
+	positionHistory.Record(p.EntityID, p.Time, p.Position, p.AABB)
+	c.checkFall()
+
+	if Dim.GetChunkContaining(core.NewVec3FromFloat(c.OldPosition.Position)) !=
+		Dim.GetChunkContaining(core.NewVec3FromFloat(c.Position.Position)) {
+		chunkPos := core.NewVec3(
+			core.FlooredDivision(core.FloorFloat32(p.Position.X()), 16)*16,
+			0,
+			core.FlooredDivision(core.FloorFloat32(p.Position.Z()), 16)*16,
+		)
+
+		// Check whether we need to unload any chunks
+		var unloadChunks []core.Vec3
+		for _, v := range c.loadedChunks {
+			if v.X-chunkPos.X < (-config.App.RenderDistance-2)*16 || v.X-chunkPos.X > (config.App.RenderDistance+2)*16 ||
+				v.Z-chunkPos.Z < (-config.App.RenderDistance-2)*16 || v.Z-chunkPos.Z > (config.App.RenderDistance+2)*16 {
+				unloadChunks = append(unloadChunks, v)
+			}
+		}
+
+		// Check whether we need to load any chunks.
+		// NB: We always load an entire chunk column
+		var newChunks []core.Vec3
+		var allChunks []core.Vec3
+		for x := -config.App.RenderDistance - 2; x < config.App.RenderDistance+2; x++ {
+			for z := -config.App.RenderDistance - 2; z < config.App.RenderDistance+2; z++ {
+				for y := 0; y < 16; y++ {
+					allChunks = append(allChunks, core.NewVec3(x*16+chunkPos.X, y*16, z*16+chunkPos.Z))
+				}
+
+				var found bool
+				for _, v := range c.loadedChunks {
+					if v.X-chunkPos.X == x*16 && v.Z-chunkPos.Z == z*16 {
+						found = true
+						break
+					}
+				}
+				if !found {
+					for y := 0; y < 16; y++ {
+						newChunks = append(newChunks, core.NewVec3(x*16+chunkPos.X, y*16, z*16+chunkPos.Z))
+					}
+				}
+			}
+		}
+
+		if len(newChunks) != 0 {
+			var chunks []*core.Chunk
+			for _, v := range newChunks {
+				chunks = append(chunks, GetChunkOrGen(v))
+			}
+
+			c.SendQueue <- proto.LOAD_CHUNKS
+			c.SendQueue <- proto.NewLoadChunks(chunks)
+			c.sendMetadataIn(newChunks)
+		}
+		if len(unloadChunks) != 0 {
+			c.SendQueue <- proto.UNLOAD_CHUNKS
+			c.SendQueue <- unloadChunks
+		}
+
+		c.loadedChunks = allChunks
+	}
+
+	// Update all other clients
+	for _, v := range clients {
+		if v != c && v.joined {
+			v.SendQueue <- proto.ENTITY_POSITION
+			v.SendQueue <- proto.EntityPosition(p)
+		}
+	}
+	Dim.Lock.Unlock()
 }
 
-// func (c *Client) HandlePlayerPosition(p proto.PlayerPosition) {
-// 	// TODO Check whether the player's position is valid
-// 	// TODO Rubberband player
-
-// 	p.EntityID = c.OldPosition.EntityID
-
-// 	c.OldPosition = c.Position
-// 	c.Position = p
-
-// 	Dim.Lock.Lock()
-// 	if Dim.GetChunkContaining(core.NewVec3FromFloat(c.OldPosition.Position)) !=
-// 		Dim.GetChunkContaining(core.NewVec3FromFloat(c.Position.Position)) {
-// 		chunkPos := core.NewVec3(
-// 			core.FlooredDivision(core.FloorFloat32(p.Position.X()), 16)*16,
-// 			0,
-// 			core.FlooredDivision(core.FloorFloat32(p.Position.Z()), 16)*16,
-// 		)
-
-// 		// Check whether we need to unload any chunks
-// 		var unloadChunks []core.Vec3
-// 		for _, v := range c.loadedChunks {
-// 			if v.X-chunkPos.X < (-config.App.RenderDistance-2)*16 || v.X-chunkPos.X > (config.App.RenderDistance+2)*16 ||
-// 				v.Z-chunkPos.Z < (-config.App.RenderDistance-2)*16 || v.Z-chunkPos.Z > (config.App.RenderDistance+2)*16 {
-// 				unloadChunks = append(unloadChunks, v)
-// 			}
-// 		}
-
-// 		// Check whether we need to load any chunks.
-// 		// NB: We always load an entire chunk column
-// 		var newChunks []core.Vec3
-// 		var allChunks []core.Vec3
-// 		for x := -config.App.RenderDistance - 2; x < config.App.RenderDistance+2; x++ {
-// 			for z := -config.App.RenderDistance - 2; z < config.App.RenderDistance+2; z++ {
-// 				for y := 0; y < 16; y++ {
-// 					allChunks = append(allChunks, core.NewVec3(x*16+chunkPos.X, y*16, z*16+chunkPos.Z))
-// 				}
-
-// 				var found bool
-// 				for _, v := range c.loadedChunks {
-// 					if v.X-chunkPos.X == x*16 && v.Z-chunkPos.Z == z*16 {
-// 						found = true
-// 						break
-// 					}
-// 				}
-// 				if !found {
-// 					for y := 0; y < 16; y++ {
-// 						newChunks = append(newChunks, core.NewVec3(x*16+chunkPos.X, y*16, z*16+chunkPos.Z))
-// 					}
-// 				}
-// 			}
-// 		}
-
-// 		if len(newChunks) != 0 {
-// 			fmt.Println(newChunks)
-// 			fmt.Println("writing load chunks")
-// 			var chunks []*core.Chunk
-// 			for _, v := range newChunks {
-// 				chunks = append(chunks, GetChunkOrGen(v))
-// 			}
-
-// 			c.SendQueue <- proto.LOAD_CHUNKS
-// 			c.SendQueue <- proto.NewLoadChunks(chunks)
-// 		}
-// 		if len(unloadChunks) != 0 {
-// 			fmt.Println("writing unload chunks")
-// 			c.SendQueue <- proto.UNLOAD_CHUNKS
-// 			c.SendQueue <- unloadChunks
-// 		}
-
-// 		c.loadedChunks = allChunks
-// 	}
-// 	Dim.Lock.Unlock()
-
-// 	// Update all other clients
-// 	for _, v := range clients {
-// 		if v != c {
-// 			v.SendQueue <- proto.ENTITY_POSITION
-// 			v.SendQueue <- p
-// 		}
-// 	}
-
-// }
-
-// func (c *Client) HandleBlockInteraction(b proto.BlockInteraction) {
-// 	// TODO Check for reach
-
-// 	// Check whether the player is inside the block
-// 	// Nasty hack to use entity function
-
-// 	selectedSlot := c.Inventory.GetSlots()[c.HotbarSlotSelected]
-
-// 	if selectedSlot.GetStack().IsEmpty() {
-// 		// TODO Error
-// 		return
-// 	}
-
-// 	Dim.Lock.Lock()
-
-// 	// Is the block clicked interactable?
-// 	old := Dim.GetBlockAt(b.Position)
-// 	if old.Type.LinkWithEntity != "" {
-// 		// Find the entity for this block
-// 		for _, v := range Dim.Entities {
-// 			if v.Position == b.Position.ToFloat() &&
-// 				core.BlockRegistry[old.Type.Name].LinkWithEntity == v.EntityType {
-
-// 				// This has an entity, try to interact
-
-// 			}
-// 		}
-// 	}
-
-// 	// Place the block
-// 	face := core.FaceFromSubvoxel(b.SubvoxelHit)
-// 	newBlock := core.Block{
-// 		Position: b.Position.Add(core.FaceDirection[face]),
-// 		// Type:     core.BlockRegistry["mc:cobblestone"],
-// 		Type: core.BlockRegistry[selectedSlot.GetStack().Item], // TODO item interact
-// 	}
-// 	Dim.SetBlockAt(newBlock)
-
-// 	// See whether the entity will intersect with this block
-// 	e := &core.Entity{Position: c.Position.Position, AABB: mgl32.Vec3{0.6, 1.8, 0.6}}
-// 	if _, intersects := e.GetBlockIntersecting(Dim); intersects {
-// 		// Remove the block
-// 		Dim.SetBlockAt(core.Block{Position: newBlock.Position, Type: nil})
-
-// 		Dim.Lock.Unlock()
-// 		return
-// 	}
-
-// 	linkEntityType := core.BlockRegistry[selectedSlot.GetStack().Item].LinkWithEntity
-// 	if linkEntityType != "" {
-// 		// Create the linked entity
-// 		e := &core.Entity{
-// 			ID:         uuid.New(),
-// 			Position:   newBlock.Position.ToFloat(),
-// 			EntityType: linkEntityType,
-// 			IsBlock:    true,
-// 		}
-
-// 		Dim.Entities = append(Dim.Entities, e)
-// 		for _, v := range clients {
-// 			v.SendQueue <- proto.ENTITY_CREATE
-// 			v.SendQueue <- proto.EntityCreate{
-// 				EntityPosition: proto.EntityPosition{
-// 					EntityID:      e.ID,
-// 					Position:      e.Position,
-// 					Yaw:           e.Yaw,
-// 					Pitch:         e.Pitch,
-// 					AABB:          e.AABB,
-// 					LookAzimuth:   e.LookAzimuth,
-// 					LookElevation: e.LookElevation,
-// 				},
-// 				EntityType: e.EntityType,
-// 			}
-// 		}
-// 	}
-
-// 	// Decrement itemstack and update clint
-// 	s := selectedSlot.GetStack()
-// 	s.Count--
-// 	if s.Count == 0 {
-// 		selectedSlot.SetStack(core.ItemStack{})
-// 	} else {
-// 		selectedSlot.SetStack(s)
-// 	}
-
-// 	c.SendQueue <- proto.CONTAINER_CONTENTS
-// 	c.SendQueue <- proto.ContainerContents{
-// 		Slots:         core.GetStacksFromSlots(c.Inventory.GetSlots()),
-// 		FloatingStack: core.ItemStack{},
-// 	}
-
-// 	// If needed update player's equipment
-// 	if selectedSlot.GetStack().Item == "" {
-// 		for _, v := range clients {
-// 			if v != c {
-// 				v.SendQueue <- proto.ENTITY_EQUIPMENT
-// 				v.SendQueue <- proto.EntityEquipment{
-// 					EntityID: c.Position.EntityID,
-// 					EntityEquipment: core.EntityEquipment{
-// 						HeldItemType: selectedSlot.GetStack().Item,
-// 					},
-// 				}
-// 			}
-// 		}
-// 	}
-
-// 	// Update clients if the chunk is loaded for them
-// 	chunkPos := Dim.GetChunkContaining(newBlock.Position).Position
-
-// 	for _, v := range clients {
-// 		for _, u := range v.loadedChunks {
-// 			if u == chunkPos {
-// 				// Update the client
-// 				v.SendQueue <- proto.BLOCK_UPDATE
-// 				v.SendQueue <- proto.BlockUpdate{
-// 					Position:  newBlock.Position,
-// 					BlockType: newBlock.Type.Name,
-// 				}
-// 				break
-// 			}
-// 		}
-// 	}
-
-// 	Dim.Lock.Unlock()
-// }
-
-// func (c *Client) HandleBlockDig(b proto.BlockDig) {
-// 	// TODO Check for reach
-// 	// TODO survival mining
-
-// 	Dim.Lock.Lock()
-
-// 	oldBlock := Dim.GetBlockAt(b.Position)
-
-// 	newBlock := core.Block{Position: b.Position, Type: nil}
-// 	Dim.SetBlockAt(newBlock)
-
-// 	// Delete any linked entities
-// 	for k, v := range Dim.Entities {
-// 		if v.Position == b.Position.ToFloat() &&
-// 			core.BlockRegistry[oldBlock.Type.Name].LinkWithEntity == v.EntityType {
-
-// 			Dim.Entities = append(Dim.Entities[:k], Dim.Entities[k+1:]...)
-
-// 			for _, w := range clients {
-// 				w.SendQueue <- proto.ENTITY_DELETE
-// 				w.SendQueue <- v.ID
-// 			}
-// 			break
-// 		}
-// 	}
-
-// 	// Update clients if the chunk is loaded for them
-// 	chunkPos := Dim.GetChunkContaining(b.Position).Position
-
-// 	for _, v := range clients {
-// 		for _, u := range v.loadedChunks {
-// 			if u == chunkPos {
-// 				// Update the client
-// 				v.SendQueue <- proto.BLOCK_UPDATE
-// 				v.SendQueue <- proto.BlockUpdate{
-// 					Position:  newBlock.Position,
-// 					BlockType: "",
-// 				}
-// 				break
-// 			}
-// 		}
-// 	}
-
-// 	Dim.Lock.Unlock()
-// }
+func (c *Client) HandleBlockInteraction(b proto.BlockInteraction) {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	if c.Health.Dead() || !c.canReachBlock(b.Position) {
+		// TODO Invalid, the client should be corrected
+		return
+	}
+
+	// Is the block clicked interactable?
+	old := Dim.GetBlockAt(b.Position)
+	if old.Type == nil {
+		// TODO Invalid
+		return
+	}
+	if old.Type.LinkWithEntity != "" {
+		e := getLinkedEntity(b.Position, old.Type.LinkWithEntity)
+		if f, ok := e.(core.ContainerFace); ok {
+			if s, ok := f.GetContainer().(core.SharedContainer); ok {
+				c.openContainer(e, s)
+				return
+			}
+		}
+	}
+
+	if !c.canInteract() {
+		return
+	}
+
+	selectedSlot := c.Inventory.GetSlots()[c.HotbarSlotSelected]
+	if selectedSlot.GetStack().IsEmpty() {
+		// TODO Error
+		return
+	}
+
+	face, ok := core.FaceFromHit(old.Type, b.SubvoxelHit)
+	if !ok {
+		// TODO Invalid
+		return
+	}
+	if c.useBucket(old, face) {
+		return
+	}
+
+	newType, ok := core.BlockRegistry[selectedSlot.GetStack().Item] // TODO item interact
+	if !ok {
+		return
+	}
+
+	// Place the block, which can replace fluids
+	newBlock := core.Block{
+		Position: b.Position.Add(core.FaceDirection[face]),
+		Type:     newType,
+	}
+	if t := Dim.GetBlockAt(newBlock.Position).Type; t != nil && t.Fluid == nil {
+		return
+	}
+	Dim.SetBlockAt(newBlock)
+	Dim.ScheduleUpdateTicks(newBlock.Position)
+
+	// TODO Check whether the player is inside the block
+
+	if newType.LinkWithEntity != "" {
+		// Create the linked entity
+		e := newLinkedEntity(newType.LinkWithEntity, newBlock.Position)
+		if e != nil {
+			Dim.Entities.Add(e)
+		}
+	}
+
+	// Decrement itemstack and update client
+	if c.GameMode.ConsumesItems() {
+		s := selectedSlot.GetStack()
+		s.Count--
+		if s.Count == 0 {
+			selectedSlot.SetStack(core.ItemStack{})
+		} else {
+			selectedSlot.SetStack(s)
+		}
+
+		c.sendContainerContents(c.Inventory)
+	}
+
+	broadcastBlockUpdate(newBlock)
+}
+
+func (c *Client) HandleBlockDig(b proto.BlockDig) {
+	Dim.Lock.Lock()
+	defer Dim.Lock.Unlock()
+
+	if !c.canInteract() || !c.canReachBlock(b.Position) {
+		// TODO Invalid, the client should be corrected
+		return
+	}
+
+	// Fluids can only be picked up with buckets
+	oldBlock := Dim.GetBlockAt(b.Position)
+	if oldBlock.Type == nil || oldBlock.Type.Fluid != nil {
+		return
+	}
+
+	if !c.GameMode.InstantBreak() {
+		if !b.FinishDigging {
+			c.digPosition = b.Position
+			c.digStarted = time.Now()
+			sendMetadata(c.Position.EntityID, core.AnimationSwing)
+			return
+		}
+
+		// Survival digging must have been started on the same block, long enough ago
+		digTime := time.Duration(oldBlock.Type.Hardness * DIG_TIME_LEEWAY * float32(time.Second))
+		if c.digStarted.IsZero() || c.digPosition != b.Position || time.Since(c.digStarted) < digTime {
+			// TODO Invalid, the client should be corrected
+			return
+		}
+		c.digStarted = time.Time{}
+	} else if !b.FinishDigging {
+		return
+	}
+
+	newBlock := core.Block{Position: b.Position, Type: nil}
+	Dim.SetBlockAt(newBlock)
+	Dim.ScheduleUpdateTicks(newBlock.Position)
+	sendMetadata(c.Position.EntityID, core.AnimationSwing)
+
+	if c.GameMode.ConsumesItems() {
+		// Give the player the block
+		// TODO Drop the block as an item
+		if _, ok := core.ItemRegistry[oldBlock.Type.Name]; ok {
+			c.giveStack(core.ItemStack{Item: oldBlock.Type.Name, Count: 1})
+			c.sendContainerContents(c.Inventory)
+		}
+	}
+
+	// Wear out the held tool
+	held := c.Inventory.GetSlots()[c.HotbarSlotSelected]
+	if s := held.GetStack(); !s.IsEmpty() && core.ItemRegistry[s.Item].MaxDamage > 0 {
+		s.AddDamage(1)
+		held.SetStack(s)
+		c.sendContainerContents(c.Inventory)
+	}
+
+	// Delete any linked entities
+	if e := getLinkedEntity(b.Position, oldBlock.Type.LinkWithEntity); e != nil {
+		// Viewers get back what they were holding, and the digger gets the
+		// contents
+		// TODO Drop the contents as item entities, once they exist
+		closeContainerSession(e.GetID())
+		if f, ok := e.(core.ContainerFace); ok {
+			for _, v := range f.GetContainer().GetSlots() {
+				c.giveStack(v.GetStack())
+				v.SetStack(core.ItemStack{})
+			}
+			c.sendContainerContents(c.Inventory)
+		}
+
+		removeEntity(e)
+	}
+
+	broadcastBlockUpdate(newBlock)
+}
+
+// Update clients if the chunk is loaded for them
+// You must lock Dim yourself
+func broadcastBlockUpdate(b core.Block) {
+	chunkPos := Dim.GetChunkContaining(b.Position).Position
+
+	var blockType string
+	if b.Type != nil {
+		blockType = b.Type.Name
+	}
+
+	for _, v := range clients {
+		for _, u := range v.loadedChunks {
+			if u == chunkPos {
+				// Update the client
+				v.SendQueue <- proto.BLOCK_UPDATE
+				v.SendQueue <- proto.BlockUpdate{
+					Position:  b.Position,
+					BlockType: blockType,
+				}
+				break
+			}
+		}
+	}
+}
+
+// Finds the entity of the type linked with the block at the position
+// You must lock Dim yourself
+func getLinkedEntity(pos core.Vec3, typeName string) core.Entity {
+	if typeName == "" {
+		return nil
+	}
+
+	for _, v := range Dim.Entities.InAABB(pos.ToFloat(), pos.ToFloat()) {
+		if v.GetTypeName() == typeName && *v.(core.PositionFace).GetPosition() == pos.ToFloat() {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// Creates a new entity to be linked with a block
+func newLinkedEntity(typeName string, pos core.Vec3) core.Entity {
+	e, ok := newEntity(typeName).(interface {
+		core.Entity
+		core.PositionFace
+	})
+	if !ok {
+		return nil
+	}
+	*e.GetPosition() = pos.ToFloat()
+	return e
+}
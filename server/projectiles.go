@@ -0,0 +1,118 @@
+package server
+
+import (
+	"remakemc/config"
+	"remakemc/core"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+// Creates a new projectile of the type shot by the owner, or returns nil if it
+// isn't a projectile
+func newProjectile(typeName string, pos mgl32.Vec3, owner uuid.UUID) core.Projectile {
+	p, ok := newEntity(typeName).(core.Projectile)
+	if !ok {
+		return nil
+	}
+	*p.GetPosition() = pos
+	p.GetProjectileComp().Owner = owner
+	return p
+}
+
+// Moves projectiles, and hurts whatever they hit
+// You must lock Dim yourself
+func tickProjectiles() {
+	ctx := &core.ProjectileContext{
+		Dim:    Dim,
+		Hit:    projectileHit,
+		Remove: func(p core.Projectile) { removeEntity(p) },
+		Rewind: rewindProjectileTarget,
+	}
+
+	for _, v := range clients {
+		if !v.joined || v.Health.Dead() || v.GameMode == core.Spectator {
+			continue
+		}
+
+		ctx.Targets = append(ctx.Targets, core.ProjectileTarget{
+			ID:       v.Position.EntityID,
+			Position: v.Position.Position,
+			AABB:     v.Position.AABB,
+		})
+	}
+	for _, v := range core.Query[interface {
+		core.Entity
+		core.PositionFace
+		core.PhysicsFace
+		core.HealthFace
+	}](&Dim.Entities) {
+		ctx.Targets = append(ctx.Targets, core.ProjectileTarget{
+			ID:       v.GetID(),
+			Position: *v.GetPosition(),
+			AABB:     v.GetPhysicsComp().AABB,
+		})
+	}
+
+	core.ProjectileSystem(ctx, 1.0/TICK_RATE)
+}
+
+// Damages and knocks back the target of the projectile
+// You must lock Dim yourself
+func projectileHit(p core.Projectile, target uuid.UUID) {
+	pc := p.GetProjectileComp()
+	dir := p.GetPhysicsComp().Velocity
+
+	if v := getClient(target); v != nil {
+		if getClient(pc.Owner) != nil && !config.App.Server.PvP {
+			return
+		}
+		if !v.GameMode.TakesDamage() {
+			return
+		}
+
+		if pc.Damage > 0 {
+			v.damage(pc.Damage)
+		}
+		v.knockback(dir)
+		return
+	}
+
+	e, ok := getEntity(target).(interface {
+		core.Entity
+		core.PhysicsFace
+		core.HealthFace
+	})
+	if !ok {
+		return
+	}
+
+	core.ApplyKnockback(e.GetPhysicsComp(), dir, core.KNOCKBACK_SPEED)
+	damageEntity(e, pc.Damage)
+}
+
+// Shoots the projectile of the item from the client's eyes, using up its
+// ammo. Returns false if there was no ammo.
+// You must lock Dim yourself
+func (c *Client) shoot(item *core.ItemType) bool {
+	if item.Ammo != "" && c.GameMode.ConsumesItems() && !c.takeItem(item.Ammo) {
+		return false
+	}
+
+	eye := c.Position.Position.Add(core.PLAYER_EYE_OFFSET)
+	p := newProjectile(item.Shoots, eye, c.Position.EntityID)
+	if p == nil {
+		return false
+	}
+
+	// Start centred on the eyes
+	*p.GetPosition() = eye.Sub(p.GetPhysicsComp().AABB.Mul(0.5))
+	dir := core.LookDirection(c.Position.LookAzimuth, c.Position.LookElevation)
+	p.GetPhysicsComp().Velocity = dir.Mul(item.ShootSpeed)
+	p.GetLookComp().Azimuth = c.Position.LookAzimuth
+	p.GetLookComp().Elevation = c.Position.LookElevation
+
+	// TODO Pick up arrows
+	spawnEntity(p)
+	return true
+}
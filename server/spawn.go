@@ -0,0 +1,236 @@
+package server
+
+import (
+	"math"
+	"remakemc/core"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+)
+
+const (
+	// The number of ticks between attempts to spawn and despawn mobs
+	SPAWN_INTERVAL = 20
+	// The number of places tried for each category around each player
+	SPAWN_ATTEMPTS = 4
+	// The radius of chunks around a player where mobs spawn, and count
+	// towards the spawn cap of the player
+	SPAWN_RADIUS = 8
+	// How far the mobs of a group can spawn from each other
+	SPAWN_GROUP_SPREAD = 3
+
+	// Mobs won't spawn closer than this to a player
+	MIN_SPAWN_DISTANCE = 24
+	// Mobs this far from every player are despawned
+	DESPAWN_DISTANCE = 128
+	// Mobs this far from every player may be despawned at random
+	RANDOM_DESPAWN_DISTANCE = 32
+	// The chance of a mob being despawned at random, every SPAWN_INTERVAL
+	RANDOM_DESPAWN_CHANCE = 1.0 / 40
+)
+
+// The mobs which spawned naturally, so can be despawned.
+// Mobs summoned from the console stay forever.
+// You must lock Dim to access this
+var naturalMobs = make(map[uuid.UUID]bool)
+
+// You must lock Dim to access this
+var spawnTicks int
+
+// Spawns mobs around players, and despawns mobs which are far away
+// You must lock Dim yourself
+func tickSpawning() {
+	spawnTicks++
+	if spawnTicks%SPAWN_INTERVAL != 0 {
+		return
+	}
+
+	var spawners []*Client
+	var players []mgl32.Vec3
+	for _, v := range clients {
+		if v.joined && v.GameMode != core.Spectator {
+			spawners = append(spawners, v)
+			players = append(players, v.Position.Position)
+		}
+	}
+
+	despawnMobs(players)
+
+	for _, v := range spawners {
+		for _, c := range core.SpawnCategories {
+			for i := 0; i < SPAWN_ATTEMPTS && countMobsAround(v.Position.Position, c) < c.Cap; i++ {
+				trySpawnGroup(v, c, players)
+			}
+		}
+	}
+}
+
+// You must lock Dim yourself
+func despawnMobs(players []mgl32.Vec3) {
+	core.Each(&Dim.Entities, func(m core.Mob) {
+		if !naturalMobs[m.GetID()] {
+			return
+		}
+
+		dist, found := nearestPlayerDistance(*m.GetPosition(), players)
+		if !found || dist > DESPAWN_DISTANCE ||
+			(dist > RANDOM_DESPAWN_DISTANCE && mobRand.Float32() < RANDOM_DESPAWN_CHANCE) {
+			removeEntity(m)
+		}
+	})
+}
+
+// The number of mobs of the category within SPAWN_RADIUS chunks of the position
+// You must lock Dim yourself
+func countMobsAround(pos mgl32.Vec3, c *core.SpawnCategory) (count int) {
+	r := mgl32.Vec3{SPAWN_RADIUS * 16, SPAWN_RADIUS * 16, SPAWN_RADIUS * 16}
+	for _, e := range Dim.Entities.InAABB(pos.Sub(r), pos.Add(r)) {
+		if rule := core.GetSpawnRule(e.GetTypeName()); rule != nil && rule.Category == c {
+			count++
+		}
+	}
+	return
+}
+
+// Tries to spawn a group of mobs of the category at a random place around
+// the client, in the chunks they have loaded, returning whether any were
+// spawned
+// You must lock Dim yourself
+func trySpawnGroup(client *Client, c *core.SpawnCategory, players []mgl32.Vec3) bool {
+	r := pickSpawnRule(c)
+	if r == nil {
+		return false
+	}
+
+	// Pick a column in a chunk around the player
+	block := core.NewVec3FromFloat(client.Position.Position)
+	chunk := core.NewVec3(core.FlooredDivision(block.X, 16), 0, core.FlooredDivision(block.Z, 16))
+	x := (chunk.X+mobRand.Intn(SPAWN_RADIUS*2+1)-SPAWN_RADIUS)*16 + mobRand.Intn(16)
+	z := (chunk.Z+mobRand.Intn(SPAWN_RADIUS*2+1)-SPAWN_RADIUS)*16 + mobRand.Intn(16)
+
+	if !client.chunkLoaded(core.NewVec3(x, 0, z)) {
+		return false
+	}
+	surface, ok := surfaceHeight(x, z)
+	if !ok {
+		return false
+	}
+	y := surface
+	if r.Placement == core.SpawnUnderground {
+		if surface <= 1 {
+			return false
+		}
+		y = 1 + mobRand.Intn(surface-1)
+	}
+
+	size := r.MinGroup
+	if r.MaxGroup > r.MinGroup {
+		size += mobRand.Intn(r.MaxGroup - r.MinGroup + 1)
+	}
+
+	var spawned bool
+	for i := 0; i < size; i++ {
+		pos := core.NewVec3(
+			x+mobRand.Intn(SPAWN_GROUP_SPREAD*2+1)-SPAWN_GROUP_SPREAD,
+			y,
+			z+mobRand.Intn(SPAWN_GROUP_SPREAD*2+1)-SPAWN_GROUP_SPREAD,
+		)
+
+		if dist, found := nearestPlayerDistance(pos.ToFloat(), players); (found && dist < MIN_SPAWN_DISTANCE) ||
+			!client.chunkLoaded(pos) {
+			continue
+		}
+
+		m := newMob(r.Entity, mgl32.Vec3{})
+		if m == nil {
+			return spawned
+		}
+
+		aabb := m.GetPhysicsComp().AABB
+		if !r.CanSpawnAt(Dim, pos, aabb, BiomeAt(pos)) {
+			continue
+		}
+
+		// Centre the mob in the space it takes up
+		*m.GetPosition() = core.PathNodeCentre(pos, aabb).Sub(mgl32.Vec3{aabb.X() / 2, 0, aabb.Z() / 2})
+		m.GetLookComp().Azimuth = mobRand.Float64() * 2 * math.Pi
+
+		spawnEntity(m)
+		naturalMobs[m.GetID()] = true
+		spawned = true
+	}
+
+	return spawned
+}
+
+// Picks a random spawn rule of the category, weighted by the rule weights
+func pickSpawnRule(c *core.SpawnCategory) *core.SpawnRule {
+	var total int
+	for _, v := range core.SpawnRules {
+		if v.Category == c {
+			total += v.Weight
+		}
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	n := mobRand.Intn(total)
+	for _, v := range core.SpawnRules {
+		if v.Category != c {
+			continue
+		}
+
+		n -= v.Weight
+		if n < 0 {
+			return v
+		}
+	}
+	return nil
+}
+
+// The height of the block above the highest solid block in the column,
+// if the column has been generated
+// You must lock Dim yourself
+func surfaceHeight(x, z int) (int, bool) {
+	// Find the top of the column
+	top := core.NewVec3(x, 0, z)
+	if Dim.GetChunkContaining(top) == nil {
+		return 0, false
+	}
+	for Dim.GetChunkContaining(top.Add(core.Vec3{Y: 16})) != nil {
+		top.Y += 16
+	}
+
+	for y := top.Y + 15; y >= 0; y-- {
+		if Dim.GetBlockAt(core.NewVec3(x, y, z)).IsSolid() {
+			return y + 1, true
+		}
+	}
+	return 0, false
+}
+
+// Whether the client has loaded the chunk containing the position
+// You must lock Dim yourself
+func (c *Client) chunkLoaded(pos core.Vec3) bool {
+	chunkPos := core.ChunkPosition(pos)
+	for _, v := range c.loadedChunks {
+		if v == chunkPos {
+			return true
+		}
+	}
+	return false
+}
+
+func nearestPlayerDistance(pos mgl32.Vec3, players []mgl32.Vec3) (float32, bool) {
+	var nearest float32
+	var found bool
+	for _, v := range players {
+		d := v.Sub(pos).Len()
+		if !found || d < nearest {
+			nearest = d
+			found = true
+		}
+	}
+	return nearest, found
+}
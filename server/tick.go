@@ -0,0 +1,47 @@
+package server
+
+import (
+	"remakemc/core"
+	"time"
+)
+
+// The number of game ticks per second
+const TICK_RATE = core.TICK_RATE
+
+var startTime = time.Now()
+
+// The time since the server started, which positions sent to clients are
+// timestamped with
+func serverTime() time.Duration {
+	return time.Since(startTime)
+}
+
+// Runs a game tick every 1/TICK_RATE seconds
+func tickLoop() {
+	t := time.NewTicker(time.Second / TICK_RATE)
+	for range t.C {
+		Dim.Lock.Lock()
+		Dim.Ticks++
+		tickSpawning()
+		tickEntities()
+		tickBlocks()
+		tickAutosave()
+		for _, v := range clients {
+			v.tick()
+		}
+		Dim.Lock.Unlock()
+	}
+}
+
+// Updates the client for a game tick
+// You must lock Dim yourself
+func (c *Client) tick() {
+	if !c.joined {
+		return
+	}
+
+	c.tickHunger()
+	c.tickVoid()
+	c.tickMetadata()
+	c.tickPing()
+}
@@ -67,3 +67,9 @@ func GetChunkOrGen(pos core.Vec3) *core.Chunk {
 
 	return Dim.Chunks[pos]
 }
+
+// The biome of the column containing the position. Terrain generation only
+// makes plains.
+func BiomeAt(pos core.Vec3) core.Biome {
+	return core.BiomePlains
+}